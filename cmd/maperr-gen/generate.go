@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"strconv"
+)
+
+// generate renders the source of the file declaring the MultiErr
+func generate(p pkg, varName string) ([]byte, error) {
+	var ignored, hashable, formats []sentinel
+	for _, s := range p.sentinels {
+		switch {
+		case s.ignore:
+			ignored = append(ignored, s)
+		case s.status == 0:
+			continue
+		case s.kind == kindFormat:
+			formats = append(formats, s)
+		default:
+			hashable = append(hashable, s)
+		}
+	}
+	if len(ignored)+len(hashable)+len(formats) == 0 {
+		return nil, errors.New("no annotated errors found")
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by maperr-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", p.name)
+	fmt.Fprintf(&buf, "import %q\n\n", maperrPath)
+	fmt.Fprintf(&buf, "var %s = maperr.NewMultiErr(\n", varName)
+	if len(ignored) > 0 {
+		fmt.Fprintf(&buf, "maperr.NewIgnoreListMapper()")
+		for _, s := range ignored {
+			if s.kind == kindFormat {
+				fmt.Fprintf(&buf, ".\nAppendf(%s)", strconv.Quote(s.text))
+			} else {
+				fmt.Fprintf(&buf, ".\nAppend(%s)", s.name)
+			}
+		}
+		fmt.Fprintf(&buf, ",\n")
+	}
+	if len(hashable) > 0 {
+		fmt.Fprintf(&buf, "maperr.NewHashableMapper()")
+		for _, s := range hashable {
+			fmt.Fprintf(&buf, ".\nAppend(%s, %s)", s.name, target(s))
+		}
+		fmt.Fprintf(&buf, ",\n")
+	}
+	if len(formats) > 0 {
+		fmt.Fprintf(&buf, "maperr.NewListMapper()")
+		for _, s := range formats {
+			fmt.Fprintf(&buf, ".\nAppendf(%s, %s)", strconv.Quote(s.text), target(s))
+		}
+		fmt.Fprintf(&buf, ",\n")
+	}
+	fmt.Fprintf(&buf, ")\n")
+
+	return format.Source(buf.Bytes())
+}
+
+// target renders the error a sentinel is mapped to
+func target(s sentinel) string {
+	if s.code != "" {
+		return fmt.Sprintf("maperr.WithStatusCode(%s, %s, %d)", strconv.Quote(s.code), strconv.Quote(s.message), s.status)
+	}
+	return fmt.Sprintf("maperr.WithStatus(%s, %d)", strconv.Quote(s.message), s.status)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestGenerate(t *testing.T) {
+	dir := filepath.Join("testdata", "users")
+	p, err := parsePackage(dir, "maperr_gen.go")
+	require.NoError(t, err)
+
+	actual, err := generate(p, "errMapper")
+	require.NoError(t, err)
+
+	golden := filepath.Join(dir, "maperr_gen.go.golden")
+	if *update {
+		require.NoError(t, os.WriteFile(golden, actual, 0o644))
+	}
+	expected, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func TestParseFile_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		given       string
+		expectedErr string
+	}{
+		{
+			name:        "unsupported declaration",
+			given:       "var (\n\t//maperr:status 404\n\tErrX = fmt.Errorf(\"x\")\n)",
+			expectedErr: "must be declared with errors.New, maperr.NewError or maperr.Errorf",
+		},
+		{
+			name:        "invalid status",
+			given:       "//maperr:status 200\nvar ErrX = errors.New(\"x\")",
+			expectedErr: `invalid status "200"`,
+		},
+		{
+			name:        "unknown option",
+			given:       "//maperr:status 404 reason=X\nvar ErrX = errors.New(\"x\")",
+			expectedErr: `unknown option "reason"`,
+		},
+		{
+			name:        "unknown directive",
+			given:       "//maperr:skip\nvar ErrX = errors.New(\"x\")",
+			expectedErr: "unknown directive maperr:skip",
+		},
+		{
+			name:        "ignored and mapped",
+			given:       "//maperr:ignore\n//maperr:status 404\nvar ErrX = errors.New(\"x\")",
+			expectedErr: "both ignored and mapped",
+		},
+		{
+			name:        "format is not a literal",
+			given:       "//maperr:status 404\nvar ErrX = maperr.Errorf(format)",
+			expectedErr: "must be a string literal",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			src := "package users\n\nimport (\n\t\"errors\"\n\t\"fmt\"\n\n\t\"github.com/iZettle/maperr/v4\"\n)\n\n" + test.given + "\n"
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "errors.go", src, parser.ParseComments)
+			require.NoError(t, err)
+
+			_, err = parseFile(fset, file)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.expectedErr)
+		})
+	}
+}
+
+func TestSplitFields(t *testing.T) {
+	fields, err := splitFields(`status 404 code=NOT_FOUND message="user \"bob\" not found"`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"status", "404", "code=NOT_FOUND", `message=user "bob" not found`}, fields)
+}
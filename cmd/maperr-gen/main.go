@@ -0,0 +1,50 @@
+// Command maperr-gen generates the MultiErr mapping the annotated sentinel errors of a package
+//
+// Errors declared with errors.New, maperr.NewError or maperr.Errorf are annotated in their doc comment:
+//
+//	//maperr:status 404 code=USER_NOT_FOUND message="user not found"
+//	var ErrUserNotFound = errors.New("user not found")
+//
+//	//maperr:ignore
+//	var ErrCanceled = errors.New("canceled")
+//
+// When the message is omitted, the text of the error is used if it has no formatting verb,
+// otherwise the text of the status.
+// Errors declared with maperr.Errorf are mapped by their format with a ListMapper,
+// the others with a HashableMapper.
+//
+// It is meant to be run with go generate:
+//
+//	//go:generate go run github.com/iZettle/maperr/v4/cmd/maperr-gen -var errMapper
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the package to scan")
+	output := flag.String("output", "maperr_gen.go", "name of the generated file, relative to dir")
+	varName := flag.String("var", "errMapper", "name of the generated MultiErr variable")
+	flag.Parse()
+
+	if err := run(*dir, *output, *varName); err != nil {
+		fmt.Fprintln(os.Stderr, "maperr-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir, output, varName string) error {
+	pkg, err := parsePackage(dir, output)
+	if err != nil {
+		return err
+	}
+	src, err := generate(pkg, varName)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, output), src, 0o644)
+}
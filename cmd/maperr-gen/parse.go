@@ -0,0 +1,289 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	maperrPath      = "github.com/iZettle/maperr/v4"
+	directivePrefix = "//maperr:"
+)
+
+// kind is how a sentinel error is declared
+type kind int
+
+const (
+	// kindHashable is an error declared with errors.New or maperr.NewError
+	kindHashable kind = iota
+	// kindFormat is an error declared with maperr.Errorf
+	kindFormat
+)
+
+// sentinel is an annotated error declaration
+type sentinel struct {
+	name string
+	kind kind
+	// text is the text of the error, or its format
+	text    string
+	ignore  bool
+	status  int
+	code    string
+	message string
+}
+
+// pkg holds the annotated errors of a package, in declaration order
+type pkg struct {
+	name      string
+	sentinels []sentinel
+}
+
+// parsePackage parses the non-test go files of dir, except the generated output
+func parsePackage(dir, output string) (pkg, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return pkg{}, err
+	}
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || name == output {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fset := token.NewFileSet()
+	var p pkg
+	for _, name := range names {
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return pkg{}, err
+		}
+		if p.name != "" && p.name != file.Name.Name {
+			return pkg{}, fmt.Errorf("found packages %s and %s in %s", p.name, file.Name.Name, dir)
+		}
+		p.name = file.Name.Name
+
+		sentinels, err := parseFile(fset, file)
+		if err != nil {
+			return pkg{}, err
+		}
+		p.sentinels = append(p.sentinels, sentinels...)
+	}
+	if p.name == "" {
+		return pkg{}, fmt.Errorf("no go files in %s", dir)
+	}
+	return p, nil
+}
+
+// parseFile returns the annotated errors declared at the top level of a file
+func parseFile(fset *token.FileSet, file *ast.File) ([]sentinel, error) {
+	imports := importNames(file)
+
+	var sentinels []sentinel
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec := spec.(*ast.ValueSpec)
+			directives := directivesOf(valueSpec.Doc, valueSpec.Comment)
+			if !genDecl.Lparen.IsValid() {
+				directives = append(directives, directivesOf(genDecl.Doc)...)
+			}
+			if len(directives) == 0 {
+				continue
+			}
+			for i, ident := range valueSpec.Names {
+				s, err := newSentinel(ident.Name, valueAt(valueSpec, i), imports, directives)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %s: %w", fset.Position(ident.Pos()), ident.Name, err)
+				}
+				sentinels = append(sentinels, s)
+			}
+		}
+	}
+	return sentinels, nil
+}
+
+// importNames returns the names under which errors and maperr are imported, keyed by path
+func importNames(file *ast.File) map[string]string {
+	names := map[string]string{}
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path[strings.LastIndex(path, "/")+1:]
+		if path == maperrPath {
+			name = "maperr"
+		}
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		names[path] = name
+	}
+	return names
+}
+
+// directivesOf returns the maperr directives of the comment groups, without their prefix
+func directivesOf(groups ...*ast.CommentGroup) []string {
+	var directives []string
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+		for _, comment := range group.List {
+			if strings.HasPrefix(comment.Text, directivePrefix) {
+				directives = append(directives, strings.TrimPrefix(comment.Text, directivePrefix))
+			}
+		}
+	}
+	return directives
+}
+
+func valueAt(spec *ast.ValueSpec, i int) ast.Expr {
+	if i < len(spec.Values) {
+		return spec.Values[i]
+	}
+	return nil
+}
+
+// newSentinel builds a sentinel from the value it is declared with and its directives
+func newSentinel(name string, value ast.Expr, imports map[string]string, directives []string) (sentinel, error) {
+	s := sentinel{name: name}
+
+	call, ok := value.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return sentinel{}, errors.New("annotated error must be declared with errors.New, maperr.NewError or maperr.Errorf")
+	}
+	switch callee(call) {
+	case imports["errors"] + ".New", imports[maperrPath] + ".NewError":
+		s.kind = kindHashable
+	case imports[maperrPath] + ".Errorf":
+		s.kind = kindFormat
+	default:
+		return sentinel{}, errors.New("annotated error must be declared with errors.New, maperr.NewError or maperr.Errorf")
+	}
+	if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+		s.text, _ = strconv.Unquote(lit.Value)
+	} else if s.kind == kindFormat {
+		return sentinel{}, errors.New("the format of maperr.Errorf must be a string literal")
+	}
+
+	for _, directive := range directives {
+		if err := s.applyDirective(directive); err != nil {
+			return sentinel{}, err
+		}
+	}
+	if s.ignore && s.status != 0 {
+		return sentinel{}, errors.New("error is both ignored and mapped to a status")
+	}
+	if s.status != 0 && s.message == "" {
+		s.message = s.text
+		if s.message == "" || strings.ContainsRune(s.message, '%') {
+			s.message = http.StatusText(s.status)
+		}
+	}
+	return s, nil
+}
+
+// callee returns the qualified name of the called function, e.g.: errors.New
+func callee(call *ast.CallExpr) string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	pkgIdent, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	return pkgIdent.Name + "." + sel.Sel.Name
+}
+
+// applyDirective applies a directive, e.g.: status 404 code=USER_NOT_FOUND message="user not found"
+func (s *sentinel) applyDirective(directive string) error {
+	fields, err := splitFields(directive)
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return errors.New("empty maperr directive")
+	}
+	switch fields[0] {
+	case "ignore":
+		if len(fields) > 1 {
+			return fmt.Errorf("unexpected %q after maperr:ignore", fields[1])
+		}
+		s.ignore = true
+		return nil
+	case "status":
+		return s.applyStatus(fields[1:])
+	}
+	return fmt.Errorf("unknown directive maperr:%s", fields[0])
+}
+
+func (s *sentinel) applyStatus(fields []string) error {
+	if len(fields) == 0 {
+		return errors.New("missing status after maperr:status")
+	}
+	status, err := strconv.Atoi(fields[0])
+	if err != nil || status < 400 || status > 599 {
+		return fmt.Errorf("invalid status %q, expected a 4xx or 5xx status", fields[0])
+	}
+	s.status = status
+
+	for _, field := range fields[1:] {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return fmt.Errorf("invalid option %q, expected key=value", field)
+		}
+		switch key {
+		case "code":
+			s.code = value
+		case "message":
+			s.message = value
+		default:
+			return fmt.Errorf("unknown option %q", key)
+		}
+	}
+	return nil
+}
+
+// splitFields splits a directive on spaces, except within double quoted values which are unquoted
+func splitFields(directive string) ([]string, error) {
+	var fields []string
+	rest := strings.TrimSpace(directive)
+	for rest != "" {
+		end := strings.IndexAny(rest, " \t\"")
+		if end == -1 {
+			fields = append(fields, rest)
+			break
+		}
+		if rest[end] != '"' {
+			fields = append(fields, rest[:end])
+			rest = strings.TrimSpace(rest[end:])
+			continue
+		}
+		quoted, err := strconv.QuotedPrefix(rest[end:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid quoted value in %q", directive)
+		}
+		value, _ := strconv.Unquote(quoted)
+		fields = append(fields, rest[:end]+value)
+		rest = strings.TrimSpace(rest[end+len(quoted):])
+	}
+	return fields, nil
+}
@@ -0,0 +1,26 @@
+package users
+
+import (
+	"errors"
+
+	"github.com/iZettle/maperr/v4"
+)
+
+//maperr:status 404 code=USER_NOT_FOUND
+var ErrUserNotFound = errors.New("user not found")
+
+var (
+	//maperr:status 423 message="user is locked"
+	ErrUserLocked = maperr.Errorf("user %s is locked")
+
+	// ErrInvalidEmail is returned when the email of a user can not be parsed
+	//maperr:status 400 code=INVALID_EMAIL message="the email is invalid"
+	ErrInvalidEmail = maperr.NewError("invalid email")
+
+	ErrCanceled = errors.New("canceled") //maperr:ignore
+
+	errNotAnnotated = errors.New("not annotated")
+)
+
+//maperr:status 409
+var ErrUserExists = maperr.Errorf("user %s exists")
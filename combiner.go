@@ -0,0 +1,75 @@
+package maperr
+
+import (
+	"errors"
+
+	"go.uber.org/multierr"
+)
+
+// Combiner defines how errors are combined together
+type Combiner interface {
+	// Append appends the given errors together. Either value may be nil.
+	Append(left, right error) error
+	// Combine combines the passed errors into a single error.
+	Combine(errList ...error) error
+}
+
+// Default combiners
+var (
+	// MultiErrCombiner combines errors using go.uber.org/multierr
+	MultiErrCombiner Combiner = multiErrCombiner{}
+	// JoinCombiner combines errors using errors.Join from the standard library
+	JoinCombiner Combiner = joinCombiner{}
+)
+
+// WithCombiner returns a copy of the MultiErr which combines errors using c,
+// when appending a mapped error or a default one. MultiErrCombiner is used by default.
+//
+// Errors combined by any Combiner, or by any error implementing Unwrap() []error,
+// are always recognised as combined errors regardless of this setting.
+func (m MultiErr) WithCombiner(c Combiner) MultiErr {
+	m.combiner = c
+	return m
+}
+
+// append appends the given errors together using the Combiner of the MultiErr
+func (m MultiErr) append(left, right error) error {
+	if m.combiner == nil {
+		return Append(left, right)
+	}
+	return m.combiner.Append(left, right)
+}
+
+// withCombiner passes the Combiner of the MultiErr to the strategies appending errors
+func (m MultiErr) withCombiner(res MapResult) MapResult {
+	if as, ok := res.(AppendStrategy); ok && m.combiner != nil {
+		return as.WithCombiner(m.combiner)
+	}
+	return res
+}
+
+type multiErrCombiner struct{}
+
+func (multiErrCombiner) Append(left, right error) error {
+	return multierr.Append(left, right)
+}
+
+func (multiErrCombiner) Combine(errList ...error) error {
+	return multierr.Combine(errList...)
+}
+
+type joinCombiner struct{}
+
+func (joinCombiner) Append(left, right error) error {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	}
+	return errors.Join(left, right)
+}
+
+func (joinCombiner) Combine(errList ...error) error {
+	return errors.Join(errList...)
+}
@@ -0,0 +1,96 @@
+package maperr_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/multierr"
+
+	"github.com/iZettle/maperr/v4"
+)
+
+func TestMultiErr_WithCombiner(t *testing.T) {
+	t.Parallel()
+
+	errOne := errors.New("one")
+	errTwo := errors.New("two")
+
+	errMapper := maperr.NewMultiErr(
+		maperr.NewHashableMapper().
+			Append(errOne, errTwo),
+		maperr.NewListMapper().
+			Append(errTwo, maperr.WithStatus("three", http.StatusConflict)),
+	).WithCombiner(maperr.JoinCombiner)
+
+	mappedErr := errMapper.Mapped(maperr.CombineJoin(errors.New("first"), errOne), errors.New("default"))
+	assert.EqualError(t, mappedErr, "first\none\ntwo")
+	assert.Len(t, multierr.Errors(mappedErr), 1, "expected a joined error, not a multierr")
+	assert.ErrorIs(t, mappedErr, errTwo)
+
+	defaultErr := errMapper.Mapped(errors.New("unknown"), errors.New("default"))
+	assert.EqualError(t, defaultErr, "unknown\ndefault")
+
+	statusErr := errMapper.MappedWithStatus(maperr.Combine(errors.New("first"), errTwo), maperr.WithStatusInternalServerError)
+	assert.EqualError(t, statusErr, "three")
+	assert.Equal(t, http.StatusConflict, statusErr.Status())
+
+	withoutCombiner := maperr.NewMultiErr(maperr.NewHashableMapper().Append(errOne, errTwo))
+	assert.EqualError(t, withoutCombiner.Mapped(errOne, nil), "one; two")
+}
+
+func TestAppendJoin(t *testing.T) {
+	t.Parallel()
+
+	errOne := errors.New("one")
+	errTwo := errors.New("two")
+
+	assert.EqualError(t, maperr.AppendJoin(errOne, errTwo), "one\ntwo")
+	assert.Equal(t, errOne, maperr.AppendJoin(errOne, nil))
+	assert.EqualError(t, maperr.CombineJoin(errOne, nil, errTwo), "one\ntwo")
+	assert.EqualError(t, maperr.Append(errOne, errTwo), "one; two")
+}
+
+func TestCombiner_Append(t *testing.T) {
+	errOne := errors.New("one")
+	errTwo := errors.New("two")
+
+	tests := []struct {
+		name     string
+		combiner maperr.Combiner
+		expected string
+	}{
+		{
+			name:     "multierr",
+			combiner: maperr.MultiErrCombiner,
+			expected: "one; two",
+		},
+		{
+			name:     "errors.Join",
+			combiner: maperr.JoinCombiner,
+			expected: "one\ntwo",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.EqualError(t, test.combiner.Append(errOne, errTwo), test.expected)
+			assert.EqualError(t, test.combiner.Combine(errOne, nil, errTwo), test.expected)
+			assert.NoError(t, test.combiner.Append(nil, nil))
+			assert.NoError(t, test.combiner.Combine())
+		})
+	}
+}
+
+func TestJoinedErrors(t *testing.T) {
+	errOne := errors.New("one")
+	errTwo := errors.New("two")
+	errThree := errors.New("three")
+	joined := errors.Join(errOne, errors.Join(maperr.Errorf("formatted %d", 1), errTwo), errThree)
+
+	assert.Equal(t, errThree, maperr.LastAppended(joined))
+	assert.Equal(t, errTwo, maperr.LastAppended(errors.Join(errOne, errors.Join(errThree, errTwo))))
+	assert.True(t, maperr.HasError(joined, "two"))
+	assert.False(t, maperr.HasError(joined, "four"))
+	assert.EqualError(t, maperr.HasEqual(joined, maperr.Errorf("formatted %d", 2)), "formatted 1")
+}
@@ -0,0 +1,189 @@
+// Package config builds a maperr.MultiErr from a declarative configuration, e.g.:
+//
+//	mappings:
+//	  - from: storage.not_found
+//	    status: 404
+//	    code: USER_NOT_FOUND
+//	    message: user not found
+//	ignore:
+//	  - storage.canceled
+//
+// The names used in the configuration are bound to sentinel errors and formats with a Registry,
+// so that public messages and statuses can be changed without touching the code returning the errors.
+//
+// The order of the configuration is only kept within each kind of rule: whatever their position in the file,
+// ignored errors take precedence over mappings, and mappings of registered errors over mappings of formats.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/iZettle/maperr/v4"
+)
+
+// Errors returned when loading an invalid configuration, wrapped with the mapping they were found in
+var (
+	ErrUnknownName       = errors.New("unknown name")
+	ErrInvalidStatus     = errors.New("invalid status")
+	ErrMissingTarget     = errors.New("missing target")
+	ErrConflictingTarget = errors.New("conflicting target")
+)
+
+// Config is the declarative configuration of a MultiErr
+type Config struct {
+	Mappings []Mapping `json:"mappings" yaml:"mappings"`
+	// Ignore lists the names of the errors to ignore
+	Ignore []string `json:"ignore" yaml:"ignore"`
+}
+
+// Mapping maps the error registered as From to either a registered error, or a new error
+// with a message and optionally a status and a code
+type Mapping struct {
+	From    string `json:"from" yaml:"from"`
+	Status  int    `json:"status,omitempty" yaml:"status,omitempty"`
+	Code    string `json:"code,omitempty" yaml:"code,omitempty"`
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	To      string `json:"to,omitempty" yaml:"to,omitempty"`
+}
+
+// Registry binds the names used in a configuration to sentinel errors and formats
+type Registry struct {
+	errs    map[string]error
+	formats map[string]string
+}
+
+// NewRegistry returns an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{
+		errs:    map[string]error{},
+		formats: map[string]string{},
+	}
+}
+
+// Register binds a name to an error, it panics if the name is already registered or if err is nil
+func (r *Registry) Register(name string, err error) *Registry {
+	if err == nil {
+		panic(fmt.Sprintf("config: name %q registered with a nil error", name))
+	}
+	r.mustBeFree(name)
+	r.errs[name] = err
+	return r
+}
+
+// RegisterFormat binds a name to the format of errors created by maperr.Errorf,
+// it panics if the name is already registered
+func (r *Registry) RegisterFormat(name, format string) *Registry {
+	r.mustBeFree(name)
+	r.formats[name] = format
+	return r
+}
+
+func (r *Registry) mustBeFree(name string) {
+	_, isErr := r.errs[name]
+	_, isFormat := r.formats[name]
+	if isErr || isFormat {
+		panic(fmt.Sprintf("config: name %q registered twice", name))
+	}
+}
+
+// LoadJSON builds a MultiErr from a JSON configuration
+func (r *Registry) LoadJSON(data []byte) (maperr.MultiErr, error) {
+	var cfg Config
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		return maperr.MultiErr{}, fmt.Errorf("config: decode json: %w", err)
+	}
+	return r.Load(cfg)
+}
+
+// LoadYAML builds a MultiErr from a YAML configuration
+func (r *Registry) LoadYAML(data []byte) (maperr.MultiErr, error) {
+	var cfg Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil {
+		return maperr.MultiErr{}, fmt.Errorf("config: decode yaml: %w", err)
+	}
+	return r.Load(cfg)
+}
+
+// Load builds a MultiErr from a configuration, every invalid mapping is reported in the returned error
+//
+// The MultiErr holds an IgnoreListMapper, a HashableMapper for the hashable registered errors,
+// and a ListMapper for the formats and the other registered errors, in that order
+func (r *Registry) Load(cfg Config) (maperr.MultiErr, error) {
+	var errs []error
+
+	ignoreMapper := maperr.NewIgnoreListMapper()
+	for i, name := range cfg.Ignore {
+		if err, ok := r.errs[name]; ok {
+			ignoreMapper = ignoreMapper.Append(err)
+			continue
+		}
+		if format, ok := r.formats[name]; ok {
+			ignoreMapper = ignoreMapper.Appendf(format)
+			continue
+		}
+		errs = append(errs, fmt.Errorf("config: ignore[%d]: %w %q", i, ErrUnknownName, name))
+	}
+
+	hashableMapper := maperr.NewHashableMapper()
+	listMapper := maperr.NewListMapper()
+	for i, mapping := range cfg.Mappings {
+		target, err := r.target(mapping)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("config: mappings[%d]: %w", i, err))
+			continue
+		}
+		if from, ok := r.errs[mapping.From]; ok {
+			if reflect.TypeOf(from).Comparable() {
+				hashableMapper = hashableMapper.Append(from, target)
+			} else {
+				listMapper = listMapper.Append(from, target)
+			}
+			continue
+		}
+		if format, ok := r.formats[mapping.From]; ok {
+			listMapper = listMapper.Appendf(format, target)
+			continue
+		}
+		errs = append(errs, fmt.Errorf("config: mappings[%d]: from: %w %q", i, ErrUnknownName, mapping.From))
+	}
+
+	if len(errs) > 0 {
+		return maperr.MultiErr{}, errors.Join(errs...)
+	}
+	return maperr.NewMultiErr(ignoreMapper, hashableMapper, listMapper), nil
+}
+
+// target returns the error a mapping maps to
+func (r *Registry) target(mapping Mapping) (error, error) {
+	switch {
+	case mapping.To != "" && (mapping.Message != "" || mapping.Status != 0 || mapping.Code != ""):
+		return nil, fmt.Errorf("%w: to %q is set along with a message, status or code", ErrConflictingTarget, mapping.To)
+	case mapping.To != "":
+		to, ok := r.errs[mapping.To]
+		if !ok {
+			return nil, fmt.Errorf("to: %w %q", ErrUnknownName, mapping.To)
+		}
+		return to, nil
+	case mapping.Message == "":
+		return nil, fmt.Errorf("%w: neither to nor message is set", ErrMissingTarget)
+	case mapping.Status == 0 && mapping.Code != "":
+		return nil, fmt.Errorf("%w: code %q requires a status", ErrInvalidStatus, mapping.Code)
+	case mapping.Status == 0:
+		return maperr.NewError(mapping.Message), nil
+	case mapping.Status < 400 || mapping.Status > 599:
+		return nil, fmt.Errorf("%w: %d is not a 4xx or 5xx status", ErrInvalidStatus, mapping.Status)
+	case mapping.Code != "":
+		return maperr.WithStatusCode(mapping.Code, mapping.Message, mapping.Status), nil
+	}
+	return maperr.WithStatus(mapping.Message, mapping.Status), nil
+}
@@ -0,0 +1,189 @@
+package config_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iZettle/maperr/v4"
+	"github.com/iZettle/maperr/v4/config"
+)
+
+var (
+	errNotFound     = errors.New("not found")
+	errCanceled     = errors.New("canceled")
+	errUserNotFound = errors.New("user not found")
+)
+
+const formatUserLocked = "user %s is locked"
+
+func newRegistry() *config.Registry {
+	return config.NewRegistry().
+		Register("storage.not_found", errNotFound).
+		Register("storage.canceled", errCanceled).
+		Register("domain.user_not_found", errUserNotFound).
+		RegisterFormat("domain.user_locked", formatUserLocked)
+}
+
+const yamlConfig = `
+mappings:
+  - from: storage.not_found
+    status: 404
+    code: USER_NOT_FOUND
+    message: user not found
+  - from: domain.user_locked
+    status: 423
+    message: user is locked
+ignore:
+  - storage.canceled
+`
+
+const jsonConfig = `{
+	"mappings": [
+		{"from": "storage.not_found", "status": 404, "code": "USER_NOT_FOUND", "message": "user not found"},
+		{"from": "domain.user_locked", "status": 423, "message": "user is locked"}
+	],
+	"ignore": ["storage.canceled"]
+}`
+
+func TestRegistry_Load(t *testing.T) {
+	loaders := map[string]func(r *config.Registry) (maperr.MultiErr, error){
+		"yaml": func(r *config.Registry) (maperr.MultiErr, error) { return r.LoadYAML([]byte(yamlConfig)) },
+		"json": func(r *config.Registry) (maperr.MultiErr, error) { return r.LoadJSON([]byte(jsonConfig)) },
+	}
+	for name, load := range loaders {
+		t.Run(name, func(t *testing.T) {
+			errMapper, err := load(newRegistry())
+			require.NoError(t, err)
+
+			notFound := errMapper.MappedWithStatus(maperr.Append(errors.New("select user"), errNotFound), nil)
+			assert.Equal(t, http.StatusNotFound, notFound.Status())
+			assert.Equal(t, "USER_NOT_FOUND", notFound.Code())
+			assert.Equal(t, "user not found", notFound.Error())
+
+			locked := errMapper.MappedWithStatus(maperr.Errorf(formatUserLocked, "bob"), nil)
+			assert.Equal(t, http.StatusLocked, locked.Status())
+			assert.Equal(t, "user is locked", locked.Error())
+
+			assert.Nil(t, errMapper.MappedWithStatus(errCanceled, maperr.WithStatusInternalServerError))
+		})
+	}
+}
+
+func TestRegistry_Load_MapsToRegisteredError(t *testing.T) {
+	errMapper, err := newRegistry().Load(config.Config{
+		Mappings: []config.Mapping{
+			{From: "storage.not_found", To: "domain.user_not_found"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, errMapper.Mapped(errNotFound, nil), errUserNotFound)
+}
+
+func TestRegistry_Load_MessageWithPercent(t *testing.T) {
+	errMapper, err := newRegistry().Load(config.Config{
+		Mappings: []config.Mapping{
+			{From: "storage.not_found", Message: "quota 100% used"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.EqualError(t, errMapper.Mapped(errNotFound, nil), "not found; quota 100% used")
+}
+
+func TestRegistry_Load_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		given       config.Config
+		expectedErr error
+	}{
+		{
+			name:        "unknown from",
+			given:       config.Config{Mappings: []config.Mapping{{From: "storage.gone", Status: 404, Message: "gone"}}},
+			expectedErr: config.ErrUnknownName,
+		},
+		{
+			name:        "unknown to",
+			given:       config.Config{Mappings: []config.Mapping{{From: "storage.not_found", To: "domain.gone"}}},
+			expectedErr: config.ErrUnknownName,
+		},
+		{
+			name:        "unknown ignored error",
+			given:       config.Config{Ignore: []string{"storage.gone"}},
+			expectedErr: config.ErrUnknownName,
+		},
+		{
+			name:        "status out of range",
+			given:       config.Config{Mappings: []config.Mapping{{From: "storage.not_found", Status: 200, Message: "ok"}}},
+			expectedErr: config.ErrInvalidStatus,
+		},
+		{
+			name:        "code without status",
+			given:       config.Config{Mappings: []config.Mapping{{From: "storage.not_found", Code: "NOT_FOUND", Message: "not found"}}},
+			expectedErr: config.ErrInvalidStatus,
+		},
+		{
+			name:        "no target",
+			given:       config.Config{Mappings: []config.Mapping{{From: "storage.not_found", Status: 404}}},
+			expectedErr: config.ErrMissingTarget,
+		},
+		{
+			name:        "both a registered error and a message",
+			given:       config.Config{Mappings: []config.Mapping{{From: "storage.not_found", To: "domain.user_not_found", Message: "not found"}}},
+			expectedErr: config.ErrConflictingTarget,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := newRegistry().Load(test.given)
+			assert.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestRegistry_LoadYAML_UnknownField(t *testing.T) {
+	_, err := newRegistry().LoadYAML([]byte("mappings:\n  - form: storage.not_found\n"))
+	assert.Error(t, err)
+}
+
+func TestRegistry_Register_Twice(t *testing.T) {
+	assert.Panics(t, func() {
+		newRegistry().RegisterFormat("storage.not_found", "not found %s")
+	})
+}
+
+func TestRegistry_Register_Nil(t *testing.T) {
+	assert.Panics(t, func() {
+		config.NewRegistry().Register("storage.nil", nil)
+	})
+}
+
+func TestRegistry_Load_Precedence(t *testing.T) {
+	errMapper, err := newRegistry().LoadYAML([]byte(`
+mappings:
+  - from: domain.user_locked
+    status: 423
+    message: user is locked
+  - from: storage.not_found
+    status: 404
+    message: user not found
+  - from: storage.canceled
+    status: 503
+    message: canceled
+ignore:
+  - storage.canceled
+`))
+	require.NoError(t, err)
+
+	// ignored errors win over mappings listed before them
+	assert.Nil(t, errMapper.MappedWithStatus(errCanceled, maperr.WithStatusInternalServerError))
+
+	// registered errors win over formats listed before them
+	mapped := errMapper.MappedWithStatus(maperr.Combine(errNotFound, maperr.Errorf(formatUserLocked, "bob")), nil)
+	require.NotNil(t, mapped)
+	assert.Equal(t, http.StatusNotFound, mapped.Status())
+}
@@ -0,0 +1,104 @@
+package maperr
+
+import (
+	"context"
+	"errors"
+)
+
+// WithStatusClientClosedRequest is the error used by the context aware methods, e.g.: MappedWithStatusContext,
+// when the context of the request was canceled, see WithCanceledErr, it is converted to codes.Canceled for gRPC
+var WithStatusClientClosedRequest = WithStatus("Client Closed Request", StatusClientClosedRequest)
+
+// ContextMapper is implemented by the mappers which read request-scoped values from the context,
+// e.g.: a locale, a tenant or an API version, when mapping with MappedContext, MappedWithStatusContext
+// or MappedWithGRPCStatusContext
+// MapErr is used when no context is available
+type ContextMapper interface {
+	Mapper
+	MapErrContext(ctx context.Context, err error) MapResult
+}
+
+// ContextMapperFunc is an adapter to allow the use of ordinary functions as ContextMapper
+type ContextMapperFunc func(ctx context.Context, err error) MapResult
+
+// MapErrContext calls f(ctx, err)
+func (f ContextMapperFunc) MapErrContext(ctx context.Context, err error) MapResult {
+	return f(ctx, err)
+}
+
+// MapErr calls f(context.Background(), err)
+func (f ContextMapperFunc) MapErr(err error) MapResult {
+	return f(context.Background(), err)
+}
+
+// mapErrContext maps err with the mapper, passing ctx when the mapper implements ContextMapper
+func mapErrContext(ctx context.Context, mapper Mapper, err error) MapResult {
+	if contextMapper, ok := mapper.(ContextMapper); ok {
+		return contextMapper.MapErrContext(ctx, err)
+	}
+	return mapper.MapErr(err)
+}
+
+// canceledErr holds the error set with WithCanceledErr
+type canceledErr struct {
+	err error
+}
+
+// WithCanceledErr returns a copy of the MultiErr which maps any error to canceled
+// when the context given to MappedContext, MappedWithStatusContext or MappedWithGRPCStatusContext was canceled,
+// whatever error the handler returned, WithStatusClientClosedRequest is used by default
+// a nil error disables the check, errors are then mapped as usual
+func (m MultiErr) WithCanceledErr(canceled error) MultiErr {
+	m.canceled = &canceledErr{err: canceled}
+	return m
+}
+
+// canceledErr returns the error to map to when ctx was canceled, nil when it was not
+func (m MultiErr) canceledErr(ctx context.Context) error {
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		return nil
+	}
+	if m.canceled == nil {
+		return WithStatusClientClosedRequest
+	}
+	return m.canceled.err
+}
+
+// lastMappedWithContext maps err to the canceled error when ctx was canceled,
+// otherwise with the mappers, passing ctx to those implementing ContextMapper
+func (m MultiErr) lastMappedWithContext(ctx context.Context, err error) (MapResult, int) {
+	if err == nil {
+		return nil, -1
+	}
+	if canceled := m.canceledErr(ctx); canceled != nil {
+		return NewAppendStrategy(err, canceled), -1
+	}
+	return m.lastMappedContext(ctx, err)
+}
+
+// MappedContext behaves like Mapped, mapping any error to the canceled error when ctx was canceled,
+// see WithCanceledErr, and passing ctx to the mappers implementing ContextMapper
+func (m MultiErr) MappedContext(ctx context.Context, err, defaultErr error) error {
+	res, mapper := m.lastMappedWithContext(ctx, err)
+	mapped := m.mappedErr(err, defaultErr, res)
+	m.notify(newObservation(err, defaultErr, res, mapper, mapped, false))
+	return mapped
+}
+
+// MappedWithStatusContext behaves like MappedWithStatus, mapping any error to the canceled error
+// when ctx was canceled, see WithCanceledErr, and passing ctx to the mappers implementing ContextMapper
+func (m MultiErr) MappedWithStatusContext(ctx context.Context, err, defaultErr error) ErrorWithStatusProvider {
+	res, mapper := m.lastMappedWithContext(ctx, err)
+	mapped, defaulted := m.mappedWithStatus(err, defaultErr, res)
+	m.notify(newObservation(err, defaultErr, res, mapper, mapped, defaulted))
+	return mapped
+}
+
+// MappedWithGRPCStatusContext behaves like MappedWithGRPCStatus, mapping any error to the canceled error
+// when ctx was canceled, see WithCanceledErr, and passing ctx to the mappers implementing ContextMapper
+func (m MultiErr) MappedWithGRPCStatusContext(ctx context.Context, err, defaultErr error) ErrorWithGRPCStatusProvider {
+	res, mapper := m.lastMappedWithContext(ctx, err)
+	mapped, defaulted := m.mappedWithGRPCStatus(err, defaultErr, res)
+	m.notify(newObservation(err, defaultErr, res, mapper, mapped, defaulted))
+	return mapped
+}
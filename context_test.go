@@ -0,0 +1,141 @@
+package maperr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"github.com/iZettle/maperr/v4"
+)
+
+type localeKey struct{}
+
+func TestMultiErr_MappedWithStatusContext(t *testing.T) {
+	errNoRows := errors.New("no rows")
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	errMapper := maperr.NewMultiErr(
+		maperr.NewHashableMapper().
+			Append(errNoRows, maperr.WithStatus("user not found", http.StatusNotFound)),
+	)
+
+	tests := []struct {
+		name           string
+		mapper         maperr.MultiErr
+		ctx            context.Context
+		given          error
+		expectedErr    string
+		expectedStatus int
+	}{
+		{
+			name:           "context not canceled",
+			mapper:         errMapper,
+			ctx:            context.Background(),
+			given:          errNoRows,
+			expectedErr:    "user not found",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "context canceled",
+			mapper:         errMapper,
+			ctx:            canceledCtx,
+			given:          fmt.Errorf("select user: %w", errNoRows),
+			expectedErr:    "Client Closed Request",
+			expectedStatus: maperr.StatusClientClosedRequest,
+		},
+		{
+			name:           "context canceled with a custom error",
+			mapper:         errMapper.WithCanceledErr(maperr.WithStatus("request canceled", http.StatusServiceUnavailable)),
+			ctx:            canceledCtx,
+			given:          errNoRows,
+			expectedErr:    "request canceled",
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "context canceled with the check disabled",
+			mapper:         errMapper.WithCanceledErr(nil),
+			ctx:            canceledCtx,
+			given:          errNoRows,
+			expectedErr:    "user not found",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := test.mapper.MappedWithStatusContext(test.ctx, test.given, maperr.WithStatusInternalServerError)
+
+			require.NotNil(t, actual)
+			assert.EqualError(t, actual, test.expectedErr)
+			assert.Equal(t, test.expectedStatus, actual.Status())
+			assert.ErrorIs(t, actual, errNoRows)
+		})
+	}
+}
+
+func TestMultiErr_MappedContext(t *testing.T) {
+	errNoRows := errors.New("no rows")
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	errMapper := maperr.NewMultiErr(
+		maperr.NewHashableMapper().
+			Append(errNoRows, errors.New("user not found")),
+	)
+
+	assert.EqualError(t, errMapper.MappedContext(context.Background(), errNoRows, nil), "no rows; user not found")
+	assert.EqualError(t, errMapper.MappedContext(canceledCtx, errNoRows, nil), "no rows; Client Closed Request")
+	assert.NoError(t, errMapper.MappedContext(canceledCtx, nil, nil))
+}
+
+func TestMultiErr_MappedWithGRPCStatusContext(t *testing.T) {
+	errNoRows := errors.New("no rows")
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	errMapper := maperr.NewMultiErr(
+		maperr.NewHashableMapper().
+			Append(errNoRows, maperr.WithCode("user not found", codes.NotFound)),
+	)
+
+	mapped := errMapper.MappedWithGRPCStatusContext(context.Background(), errNoRows, maperr.WithCodeInternal)
+	require.NotNil(t, mapped)
+	assert.Equal(t, codes.NotFound, mapped.GRPCCode())
+
+	canceled := errMapper.MappedWithGRPCStatusContext(canceledCtx, errNoRows, maperr.WithCodeInternal)
+	require.NotNil(t, canceled)
+	assert.Equal(t, codes.Canceled, canceled.GRPCCode())
+	assert.EqualError(t, canceled, "Client Closed Request")
+	assert.ErrorIs(t, canceled, errNoRows)
+
+	assert.Nil(t, errMapper.MappedWithGRPCStatusContext(canceledCtx, nil, maperr.WithCodeInternal))
+}
+
+func TestContextMapper(t *testing.T) {
+	errNoRows := errors.New("no rows")
+
+	localized := maperr.ContextMapperFunc(func(ctx context.Context, err error) maperr.MapResult {
+		if !errors.Is(err, errNoRows) {
+			return nil
+		}
+		if ctx.Value(localeKey{}) == "sv" {
+			return maperr.NewAppendStrategy(err, maperr.WithStatus("användaren hittades inte", http.StatusNotFound))
+		}
+		return maperr.NewAppendStrategy(err, maperr.WithStatus("user not found", http.StatusNotFound))
+	})
+
+	errMapper := maperr.NewMultiErr(maperr.NewPipeline(maperr.NewMultiErr(localized)))
+
+	ctx := context.WithValue(context.Background(), localeKey{}, "sv")
+	assert.EqualError(t, errMapper.MappedWithStatusContext(ctx, errNoRows, nil), "användaren hittades inte")
+	assert.EqualError(t, errMapper.MappedWithStatus(errNoRows, nil), "user not found")
+}
@@ -3,6 +3,7 @@ package maperr
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // Error which exposes a method that determines if the error
@@ -12,6 +13,10 @@ type Error interface {
 	Equal(error) bool
 	Hashable() error
 	Is(err error) bool
+	// Format returns the format the error was created from, its text when created without format
+	Format() string
+	// Args returns the arguments the error was formatted with
+	Args() []interface{}
 }
 
 // Errorf returns an error which persists
@@ -26,17 +31,17 @@ func castError(err error) Error {
 		return nil
 	}
 
-	var mapError Error
-	if errors.As(err, &mapError) {
+	if mapError, ok := asChain[Error](err); ok {
 		return mapError
 	}
 
 	return NewError(err.Error())
 }
 
-// NewError instantiates an Error with no formatting
+// NewError instantiates an Error with no formatting,
+// errText is kept as is, including any '%' it contains
 func NewError(errText string) Error {
-	return Errorf(errText)
+	return newUnformattedError(errText)
 }
 
 // formattedError is a error that holds the format
@@ -56,6 +61,15 @@ func newFormattedError(format string, args ...interface{}) formattedError {
 	}
 }
 
+// newUnformattedError return instance of formattedError
+// which only holds a format, and is compared to other errors by that format
+func newUnformattedError(format string) formattedError {
+	return formattedError{
+		format: format,
+		err:    errors.New(format),
+	}
+}
+
 // Error return the actual error
 func (fe formattedError) Error() string {
 	return fe.err.Error()
@@ -66,6 +80,16 @@ func (fe formattedError) Unwrap() error {
 	return fe.err
 }
 
+// Format returns the format the error was created from
+func (fe formattedError) Format() string {
+	return fe.format
+}
+
+// Args returns a copy of the arguments the error was formatted with
+func (fe formattedError) Args() []interface{} {
+	return append([]interface{}(nil), fe.args...)
+}
+
 // Error return the hashable error
 func (fe formattedError) Hashable() error {
 	return fe.err
@@ -86,3 +110,22 @@ func (fe formattedError) Equal(err error) bool {
 	}
 	return fe.Error() == err.Error()
 }
+
+// argsOf returns the arguments of a formatted error, nil for any other error
+func argsOf(err error) []interface{} {
+	var ferr formattedError
+	if errors.As(err, &ferr) {
+		return ferr.args
+	}
+	return nil
+}
+
+// formatOf returns the format of a formatted error, empty for any other error
+// or when the format has no verb, in which case it is compared as plain text
+func formatOf(err error) string {
+	var ferr formattedError
+	if errors.As(err, &ferr) && strings.ContainsRune(ferr.format, '%') {
+		return ferr.format
+	}
+	return ""
+}
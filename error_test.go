@@ -21,6 +21,23 @@ func TestNewError(t *testing.T) {
 	}
 }
 
+func TestNewError_PercentText(t *testing.T) {
+	err := NewError("disk 100% full")
+	assert.EqualError(t, err, "disk 100% full")
+	assert.Equal(t, "disk 100% full", err.Format())
+	assert.Empty(t, err.Args())
+	assert.True(t, err.Equal(errors.New("disk 100% full")))
+}
+
+func TestAppendf_KeepsFormatAsText(t *testing.T) {
+	listRules := NewListMapper().Appendf("user %s is locked", errors.New("locked")).Rules()
+	ignoreRules := NewIgnoreListMapper().Appendf("user %s is locked").Rules()
+
+	assert.EqualError(t, listRules[0].Key, "user %s is locked")
+	assert.EqualError(t, ignoreRules[0].Key, "user %s is locked")
+	assert.True(t, listRules[0].Key.(Error).Equal(Errorf("user %s is locked", "bob")))
+}
+
 func TestCastError_FromErrorWithStatus(t *testing.T) {
 	errWithStatus := WithStatus("BAD-REQUEST", http.StatusBadRequest)
 
@@ -0,0 +1,159 @@
+package maperr
+
+import (
+	"errors"
+	"net/http"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WithCode return an error with an associated gRPC status code
+func WithCode(err string, code codes.Code) error {
+	return errorWithCode{
+		err:  errors.New(err),
+		code: code,
+	}
+}
+
+type errorWithCode struct {
+	err    error
+	code   codes.Code
+	reason string
+	cause  error
+}
+
+func newErrorWithCode(err, cause error, code codes.Code) errorWithCode {
+	return errorWithCode{
+		err:   err,
+		code:  code,
+		cause: cause,
+	}
+}
+
+func (ewc errorWithCode) GRPCCode() codes.Code {
+	return ewc.code
+}
+
+// GRPCStatus allows status.FromError and status.Code to retrieve the gRPC status
+// the machine-readable code set with WithStatusCode is added as the reason of an ErrorInfo detail
+func (ewc errorWithCode) GRPCStatus() *status.Status {
+	st := status.New(ewc.code, ewc.Error())
+	if ewc.reason == "" {
+		return st
+	}
+	if withDetails, err := st.WithDetails(&errdetails.ErrorInfo{Reason: ewc.reason}); err == nil {
+		return withDetails
+	}
+	return st
+}
+
+func (ewc errorWithCode) Unwrap() error {
+	return ewc.cause
+}
+
+func (ewc errorWithCode) Error() string {
+	return ewc.err.Error()
+}
+
+// Format returns the format of the message of the error
+func (ewc errorWithCode) Format() string {
+	return castError(ewc.err).Format()
+}
+
+// Args returns the arguments the message of the error was formatted with
+func (ewc errorWithCode) Args() []interface{} {
+	return castError(ewc.err).Args()
+}
+
+func (ewc errorWithCode) Hashable() error {
+	return ewc
+}
+
+// Is is an alias for Equal added to support go 1.13 errors
+func (ewc errorWithCode) Is(err error) bool {
+	return ewc.Equal(err)
+}
+
+func (ewc errorWithCode) Equal(err error) bool {
+	if err == nil {
+		return false
+	}
+	var errWithCode errorWithCode
+	if errors.As(err, &errWithCode) {
+		return errors.Is(ewc.err, errWithCode.err)
+	}
+	return false
+}
+
+// errorWithGRPCStatus is an error which was not mapped but already carries a gRPC status
+type errorWithGRPCStatus struct {
+	err error
+	st  *status.Status
+}
+
+func newErrorWithGRPCStatus(err error, st *status.Status) errorWithGRPCStatus {
+	return errorWithGRPCStatus{
+		err: err,
+		st:  st,
+	}
+}
+
+func (ews errorWithGRPCStatus) GRPCCode() codes.Code {
+	return ews.st.Code()
+}
+
+// GRPCStatus returns the gRPC status the error already carried
+func (ews errorWithGRPCStatus) GRPCStatus() *status.Status {
+	return ews.st
+}
+
+func (ews errorWithGRPCStatus) Unwrap() error {
+	return ews.err
+}
+
+func (ews errorWithGRPCStatus) Error() string {
+	return ews.err.Error()
+}
+
+// codeFromHTTPStatus converts an http status to the closest gRPC status code,
+// following the mapping documented in google/rpc/code.proto
+func codeFromHTTPStatus(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusOK:
+		return codes.OK
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusRequestedRangeNotSatisfiable:
+		return codes.OutOfRange
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case StatusClientClosedRequest:
+		return codes.Canceled
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+
+	switch {
+	case httpStatus >= 400 && httpStatus < 500:
+		return codes.FailedPrecondition
+	case httpStatus >= 500 && httpStatus < 600:
+		return codes.Internal
+	}
+	return codes.Unknown
+}
@@ -0,0 +1,96 @@
+package maperr
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorWithCode_GRPCStatus(t *testing.T) {
+	errWithCode := newErrorWithCode(errors.New("user not found"), errors.New("no rows"), codes.NotFound)
+
+	st, ok := status.FromError(errWithCode)
+	if !ok {
+		t.Fatalf("expected %s to have a gRPC status", errWithCode)
+	}
+	assert.Equal(t, codes.NotFound, st.Code())
+	assert.Equal(t, "user not found", st.Message())
+	assert.Equal(t, codes.NotFound, status.Code(errWithCode))
+}
+
+func TestErrorWithCode_Is(t *testing.T) {
+	errMissingField := errors.New("MISSING_FIELD")
+	left := newErrorWithCode(errMissingField, errors.New("could not fill struct"), codes.InvalidArgument)
+	right := newErrorWithCode(errMissingField, errors.New("could not fill struct"), codes.InvalidArgument)
+
+	if !left.Is(right) {
+		t.Fatalf("expected %s to be the same error as %s", left, right)
+	}
+}
+
+func TestErrorWithCode_Is_NotTheSameError(t *testing.T) {
+	left := newErrorWithCode(errors.New("MISSING_FIELD_ONE"), errors.New("could not fill struct"), codes.InvalidArgument)
+	right := newErrorWithCode(errors.New("MISSING_FIELD_TWO"), errors.New("could not fill struct"), codes.InvalidArgument)
+
+	if left.Is(right) {
+		t.Fatalf("expected %s to be the different error than %s", left, right)
+	}
+}
+
+func TestErrorWithCode_Equal_NilError(t *testing.T) {
+	errWithCode := newErrorWithCode(nil, errors.New("could not fill struct"), codes.InvalidArgument)
+
+	if errWithCode.Equal(nil) {
+		t.Fatalf("expected %s to be the different error than nil", errWithCode)
+	}
+}
+
+func TestCastError_FromErrorWithCode(t *testing.T) {
+	errWithCode := WithCode("NOT_FOUND", codes.NotFound)
+
+	if !errors.Is(castError(errWithCode), errWithCode) {
+		t.Fatalf("expected %s to keep its gRPC code", errWithCode)
+	}
+}
+
+func TestCodeFromHTTPStatus(t *testing.T) {
+	tests := []struct {
+		status   int
+		expected codes.Code
+	}{
+		{status: http.StatusBadRequest, expected: codes.InvalidArgument},
+		{status: http.StatusNotFound, expected: codes.NotFound},
+		{status: http.StatusConflict, expected: codes.AlreadyExists},
+		{status: StatusClientClosedRequest, expected: codes.Canceled},
+		{status: http.StatusTeapot, expected: codes.FailedPrecondition},
+		{status: http.StatusInternalServerError, expected: codes.Internal},
+		{status: http.StatusBadGateway, expected: codes.Internal},
+		{status: http.StatusGatewayTimeout, expected: codes.DeadlineExceeded},
+		{status: 0, expected: codes.Unknown},
+	}
+	for _, test := range tests {
+		t.Run(http.StatusText(test.status), func(t *testing.T) {
+			assert.Equal(t, test.expected, codeFromHTTPStatus(test.status))
+		})
+	}
+}
+
+func TestErrorWithCode_GRPCStatus_Reason(t *testing.T) {
+	errWithCode := appendCauseToErrWithCode(
+		WithStatusCode("USER_NOT_FOUND", "user was not found", http.StatusNotFound),
+		errors.New("no rows"))
+
+	st := status.Convert(errWithCode)
+	assert.Equal(t, codes.NotFound, st.Code())
+	if assert.Len(t, st.Details(), 1) {
+		errInfo, ok := st.Details()[0].(*errdetails.ErrorInfo)
+		if assert.True(t, ok) {
+			assert.Equal(t, "USER_NOT_FOUND", errInfo.Reason)
+		}
+	}
+}
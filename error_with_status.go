@@ -4,18 +4,93 @@ import (
 	"errors"
 )
 
+// StatusClientClosedRequest is the non standard http status used when
+// the client closed the request before the server could answer
+const StatusClientClosedRequest = 499
+
+// StatusOption allows to set optional details on an error with status
+type StatusOption func(*errorWithStatus)
+
+// ProblemType sets the URI identifying the problem type, used when rendering
+// the error as a problem details document (RFC 9457)
+func ProblemType(uri string) StatusOption {
+	return func(ews *errorWithStatus) {
+		ews.problem = ews.problem.withType(uri)
+	}
+}
+
+// ProblemExtension sets an extension member, used when rendering
+// the error as a problem details document (RFC 9457)
+func ProblemExtension(name string, value interface{}) StatusOption {
+	return func(ews *errorWithStatus) {
+		ews.problem = ews.problem.withExtension(name, value)
+	}
+}
+
 // WithStatus return an error with an associated status
-func WithStatus(err string, status int) error {
-	return errorWithStatus{
+func WithStatus(err string, status int, opts ...StatusOption) error {
+	ews := errorWithStatus{
 		err:    errors.New(err),
 		status: status,
 	}
+	for _, opt := range opts {
+		opt(&ews)
+	}
+	return ews
+}
+
+// WithStatusCode return an error with an associated status and a stable, machine-readable code
+// errors with a code are compared by code, so the message can change without breaking comparisons
+// e.g.: maperr.WithStatusCode("USER_NOT_FOUND", "user was not found", http.StatusNotFound)
+func WithStatusCode(code, err string, status int, opts ...StatusOption) error {
+	ews := WithStatus(err, status, opts...).(errorWithStatus)
+	ews.code = code
+	return ews
 }
 
 type errorWithStatus struct {
-	err    error
-	status int
-	cause  error
+	err     error
+	code    string
+	status  int
+	cause   error
+	problem *problemDetails
+	// template renders the message from the arguments of the mapped error, see MessageTemplate
+	template *messageTemplate
+}
+
+// problemDetails holds the optional members of a problem details document
+// it is held by pointer so errorWithStatus stays comparable
+type problemDetails struct {
+	typeURI    string
+	extensions map[string]interface{}
+}
+
+// withType returns a copy of the problemDetails with the given type
+func (pd *problemDetails) withType(uri string) *problemDetails {
+	cpy := pd.copy()
+	cpy.typeURI = uri
+	return cpy
+}
+
+// withExtension returns a copy of the problemDetails with the given extension member
+func (pd *problemDetails) withExtension(name string, value interface{}) *problemDetails {
+	cpy := pd.copy()
+	cpy.extensions[name] = value
+	return cpy
+}
+
+func (pd *problemDetails) copy() *problemDetails {
+	cpy := &problemDetails{
+		extensions: map[string]interface{}{},
+	}
+	if pd == nil {
+		return cpy
+	}
+	cpy.typeURI = pd.typeURI
+	for name, value := range pd.extensions {
+		cpy.extensions[name] = value
+	}
+	return cpy
 }
 
 func newErrorWithStatus(err, cause error, status int) errorWithStatus {
@@ -30,6 +105,27 @@ func (ews errorWithStatus) Status() int {
 	return ews.status
 }
 
+// Code returns the machine-readable code of the error, empty when not set
+func (ews errorWithStatus) Code() string {
+	return ews.code
+}
+
+// ProblemType returns the URI identifying the problem type, empty when not set
+func (ews errorWithStatus) ProblemType() string {
+	if ews.problem == nil {
+		return ""
+	}
+	return ews.problem.typeURI
+}
+
+// ProblemExtensions returns a copy of the extension members of the problem
+func (ews errorWithStatus) ProblemExtensions() map[string]interface{} {
+	if ews.problem == nil {
+		return nil
+	}
+	return ews.problem.copy().extensions
+}
+
 func (ews errorWithStatus) Unwrap() error {
 	return ews.cause
 }
@@ -38,6 +134,16 @@ func (ews errorWithStatus) Error() string {
 	return ews.err.Error()
 }
 
+// Format returns the format of the message of the error
+func (ews errorWithStatus) Format() string {
+	return castError(ews.err).Format()
+}
+
+// Args returns the arguments the message of the error was formatted with
+func (ews errorWithStatus) Args() []interface{} {
+	return castError(ews.err).Args()
+}
+
 func (ews errorWithStatus) Hashable() error {
 	return ews
 }
@@ -52,8 +158,11 @@ func (ews errorWithStatus) Equal(err error) bool {
 		return false
 	}
 	var errWithStatus errorWithStatus
-	if errors.As(err, &errWithStatus) {
-		return errors.Is(ews.err, errWithStatus.err)
+	if !errors.As(err, &errWithStatus) {
+		return false
+	}
+	if ews.code != "" || errWithStatus.code != "" {
+		return ews.code == errWithStatus.code
 	}
-	return false
+	return errors.Is(ews.err, errWithStatus.err)
 }
@@ -68,3 +68,78 @@ func TestErrorWithStatus_Equal_NilError(t *testing.T) {
 		t.Fatalf("expected %s to be the different error than nil", errWithStatus)
 	}
 }
+
+func TestWithStatus_ProblemOptions(t *testing.T) {
+	err := WithStatus("out of credit", http.StatusForbidden,
+		ProblemType("https://example.com/probs/out-of-credit"),
+		ProblemExtension("balance", 30))
+
+	var provider ProblemProvider
+	if !errors.As(err, &provider) {
+		t.Fatalf("expected %s to implement ProblemProvider", err)
+	}
+	if provider.ProblemType() != "https://example.com/probs/out-of-credit" {
+		t.Fatalf("expected problem type got %s", provider.ProblemType())
+	}
+
+	extensions := provider.ProblemExtensions()
+	if extensions["balance"] != 30 {
+		t.Fatalf("expected balance extension got %v", extensions)
+	}
+
+	// extensions returned are a copy which can not alter the error
+	extensions["balance"] = 0
+	if provider.ProblemExtensions()["balance"] != 30 {
+		t.Fatalf("expected extensions to be immutable got %v", provider.ProblemExtensions())
+	}
+
+	// the error can still be used as a map key
+	_ = HashableMapper{}.Append(err, errors.New("mapped"))
+}
+
+func TestErrorWithStatus_Equal_Code(t *testing.T) {
+	tests := []struct {
+		name     string
+		left     error
+		right    error
+		expected bool
+	}{
+		{
+			name:     "same code with different messages",
+			left:     WithStatusCode("USER_NOT_FOUND", "user was not found", http.StatusNotFound),
+			right:    WithStatusCode("USER_NOT_FOUND", "user does not exist", http.StatusNotFound),
+			expected: true,
+		},
+		{
+			name:     "different codes with the same message",
+			left:     WithStatusCode("USER_NOT_FOUND", "not found", http.StatusNotFound),
+			right:    WithStatusCode("ACCOUNT_NOT_FOUND", "not found", http.StatusNotFound),
+			expected: false,
+		},
+		{
+			name:     "only one error has a code",
+			left:     WithStatusCode("USER_NOT_FOUND", "not found", http.StatusNotFound),
+			right:    WithStatus("not found", http.StatusNotFound),
+			expected: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if errors.Is(test.left, test.right) != test.expected {
+				t.Fatalf("expected errors.Is(%s, %s) to be %t", test.left, test.right, test.expected)
+			}
+		})
+	}
+}
+
+func TestWithStatusCode_Code(t *testing.T) {
+	err := WithStatusCode("USER_NOT_FOUND", "user was not found", http.StatusNotFound)
+
+	var errWithStatus ErrorWithStatusProvider
+	if !errors.As(err, &errWithStatus) {
+		t.Fatalf("expected %s to implement ErrorWithStatusProvider", err)
+	}
+	if errWithStatus.Code() != "USER_NOT_FOUND" || errWithStatus.Status() != http.StatusNotFound {
+		t.Fatalf("expected code USER_NOT_FOUND and status 404 got %s and %d", errWithStatus.Code(), errWithStatus.Status())
+	}
+}
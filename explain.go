@@ -0,0 +1,113 @@
+package maperr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Explainer is implemented by the mappers which can describe
+// how they compared an error with their rules
+type Explainer interface {
+	// ExplainErr lists the comparisons made by MapErr, in the order they were made
+	ExplainErr(err error) []Attempt
+}
+
+// MapperTrace describes how a Mapper of a MultiErr handled an error
+type MapperTrace struct {
+	// Index is the position of the Mapper within the MultiErr
+	Index int
+	// Mapper is the Mapper which was tried
+	Mapper Mapper
+	// Attempts lists the comparisons made by the Mapper, nil when it does not implement Explainer
+	Attempts []Attempt
+	// Result is the result of the Mapper, nil when the error was not matched
+	Result MapResult
+}
+
+// Outcome returns what the Mapper decided to do with the error
+func (mt MapperTrace) Outcome() Outcome {
+	if !isMatched(mt.Result) {
+		return OutcomePassedThrough
+	}
+	return mt.Result.Outcome()
+}
+
+// Explanation describes how a MultiErr handled an error
+type Explanation struct {
+	// Err is the error which was explained
+	Err error
+	// Mappers lists the mappers which were tried, in the order they were tried
+	Mappers []MapperTrace
+	// Result is the result of the Mapper which matched the error, nil when none did
+	Result MapResult
+	// Outcome is what the MultiErr decided to do with the error
+	Outcome Outcome
+}
+
+// String returns a human readable representation of the explanation,
+// useful in debug endpoints or in test failure messages
+func (e Explanation) String() string {
+	if e.Err == nil {
+		return "no error to map"
+	}
+
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "error %q was %s", e.Err.Error(), e.Outcome)
+	if e.Result != nil {
+		_, _ = fmt.Fprintf(&sb, " using %T", e.Result)
+	}
+	for _, trace := range e.Mappers {
+		_, _ = fmt.Fprintf(&sb, "\nmapper #%d %T: %s", trace.Index, trace.Mapper, trace.Outcome())
+		for _, attempt := range trace.Attempts {
+			_, _ = fmt.Fprintf(&sb, "\n\t%s", attempt)
+		}
+	}
+	return sb.String()
+}
+
+// Explain describes how the error would be handled by Mapped and MappedWithStatus:
+// which mappers were tried, which errors they compared, which rule matched or why none did
+// and what was finally decided
+// every mapper is tried unless the precedence is PrecedenceMapperOrder
+func (m MultiErr) Explain(err error) Explanation {
+	explanation := Explanation{
+		Err:     err,
+		Outcome: OutcomeNil,
+	}
+	if err == nil {
+		return explanation
+	}
+
+	for k := range m.mappers {
+		trace := MapperTrace{
+			Index:  k,
+			Mapper: m.mappers[k],
+			Result: m.mappers[k].MapErr(err),
+		}
+		if explainer, ok := m.mappers[k].(Explainer); ok {
+			trace.Attempts = explainer.ExplainErr(err)
+		}
+		explanation.Mappers = append(explanation.Mappers, trace)
+
+		if m.precedence == PrecedenceMapperOrder && isMatched(trace.Result) {
+			explanation.Result = trace.Result
+			explanation.Outcome = trace.Result.Outcome()
+			return explanation
+		}
+	}
+
+	if m.precedence != PrecedenceMapperOrder {
+		results := make([]MapResult, len(explanation.Mappers))
+		for k := range explanation.Mappers {
+			results[k] = explanation.Mappers[k].Result
+		}
+		if picked, _ := m.precedence.pick(results); picked != nil {
+			explanation.Result = picked
+			explanation.Outcome = picked.Outcome()
+			return explanation
+		}
+	}
+
+	explanation.Outcome = OutcomeDefaulted
+	return explanation
+}
@@ -0,0 +1,107 @@
+package maperr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iZettle/maperr/v4"
+)
+
+func TestMultiErr_Explain(t *testing.T) {
+	errNoRows := errors.New("no rows")
+	errIgnored := errors.New("ignored")
+	errUserNotFound := maperr.WithStatus("user not found", http.StatusNotFound)
+
+	errMapper := maperr.NewMultiErr(
+		maperr.NewHashableMapper().
+			Append(errNoRows, errUserNotFound),
+		maperr.NewListMapper().
+			Append(errors.New("conflict"), errors.New("already exists")).
+			Appendf("user %s is locked", errors.New("locked")),
+		maperr.NewIgnoreListMapper().
+			Append(errIgnored),
+		maperr.NewTypeMapper(func(err *json.SyntaxError) error {
+			return maperr.WithStatusBadRequest
+		}),
+	)
+
+	t.Run("error is mapped by a formatted rule", func(t *testing.T) {
+		explanation := errMapper.Explain(maperr.Combine(errors.New("first"), maperr.Errorf("user %s is locked", "bob")))
+
+		assert.Equal(t, maperr.OutcomeMapped, explanation.Outcome)
+		assert.IsType(t, maperr.AppendStrategy{}, explanation.Result)
+		if assert.Len(t, explanation.Mappers, 2) {
+			assert.Equal(t, maperr.OutcomePassedThrough, explanation.Mappers[0].Outcome())
+			assert.Equal(t, maperr.OutcomeMapped, explanation.Mappers[1].Outcome())
+
+			attempts := explanation.Mappers[1].Attempts
+			if assert.Len(t, attempts, 1) {
+				assert.True(t, attempts[0].Matched)
+				assert.Equal(t, 1, attempts[0].Rule.Index)
+				assert.Equal(t, "user %s is locked", attempts[0].Rule.Format)
+				assert.EqualError(t, attempts[0].Rule.Target, "locked")
+			}
+		}
+		assert.Equal(t, `error "first; user bob is locked" was mapped using maperr.AppendStrategy
+mapper #0 maperr.HashableMapper: passed through
+	"user bob is locked" not matched: no rule matched
+	"user bob is locked" not matched: no rule matched
+	"first" not matched: no rule matched
+mapper #1 maperr.ListMapper: mapped
+	"user bob is locked" matched rule #1 format "user %s is locked" -> "locked"`, explanation.String())
+	})
+
+	t.Run("error is ignored", func(t *testing.T) {
+		explanation := errMapper.Explain(fmt.Errorf("delete: %w", errIgnored))
+
+		assert.Equal(t, maperr.OutcomeIgnored, explanation.Outcome)
+		assert.Len(t, explanation.Mappers, 3)
+		assert.Contains(t, explanation.String(), `"ignored" matched rule #0 error "ignored" -> ignored`)
+	})
+
+	t.Run("error is not matched", func(t *testing.T) {
+		explanation := errMapper.Explain(errors.New("boom"))
+
+		assert.Equal(t, maperr.OutcomeDefaulted, explanation.Outcome)
+		assert.Nil(t, explanation.Result)
+		assert.Len(t, explanation.Mappers, 4)
+		assert.Equal(t, `error "boom" was defaulted
+mapper #0 maperr.HashableMapper: passed through
+	"boom" not matched: no rule matched
+mapper #1 maperr.ListMapper: passed through
+	"boom" not matched: no rule matched
+mapper #2 maperr.IgnoreListMapper: passed through
+	"boom" not matched: no rule matched
+mapper #3 maperr.TypeMapper[*encoding/json.SyntaxError]: passed through
+	"boom" not matched: error is not of type *json.SyntaxError`, explanation.String())
+	})
+
+	t.Run("error is mapped by a hashable rule", func(t *testing.T) {
+		explanation := errMapper.Explain(errNoRows)
+
+		assert.Equal(t, maperr.OutcomeMapped, explanation.Outcome)
+		assert.Equal(t, errUserNotFound, explanation.Result.Last())
+		assert.Contains(t, explanation.String(), `"no rows" matched rule error "no rows" -> "user not found"`)
+	})
+
+	t.Run("error is nil", func(t *testing.T) {
+		explanation := errMapper.Explain(nil)
+
+		assert.Empty(t, explanation.Mappers)
+		assert.Equal(t, "no error to map", explanation.String())
+	})
+}
+
+func TestMultiErr_Explain_CustomMapper(t *testing.T) {
+	explanation := maperr.NewMultiErr(timeoutMapper{match: errors.New("timeout")}).Explain(timeoutErr{})
+
+	assert.Equal(t, maperr.OutcomeMapped, explanation.Outcome)
+	if assert.Len(t, explanation.Mappers, 1) {
+		assert.Nil(t, explanation.Mappers[0].Attempts)
+	}
+}
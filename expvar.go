@@ -0,0 +1,116 @@
+package maperr
+
+import (
+	"expvar"
+	"strconv"
+	"sync"
+)
+
+// expvarMu serializes the lookup and creation of the published maps,
+// as expvar.Get and expvar.NewMap can not be done atomically
+var expvarMu sync.Mutex
+
+// expvarObserver is an Observer publishing counters with the expvar package
+type expvarObserver struct {
+	rules     *expvar.Map
+	outcomes  *expvar.Map
+	statuses  *expvar.Map
+	grpcCodes *expvar.Map
+}
+
+// NewExpvarObserver returns an Observer publishing counters under name with the expvar package,
+// available on /debug/vars when the expvar handler is registered:
+//
+//	rules       number of errors matched by each rule, e.g.: "mapper #0 rule #1 error \"no rows\""
+//	outcomes    number of errors mapped, ignored, defaulted, passed through or nil
+//	statuses    number of errors returned by MappedWithStatus for each http status
+//	grpc_codes  number of errors returned by MappedWithGRPCStatus for each gRPC code
+//
+// Observers created with the same name share the same counters.
+// When name is already used by another kind of expvar.Var, e.g.: "cmdline",
+// the counters are still kept but not published.
+func NewExpvarObserver(name string) Observer {
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+
+	var root *expvar.Map
+	switch published := expvar.Get(name).(type) {
+	case nil:
+		root = expvar.NewMap(name)
+	case *expvar.Map:
+		root = published
+	default:
+		root = new(expvar.Map).Init()
+	}
+	return expvarObserver{
+		rules:     getOrAddMap(root, "rules"),
+		outcomes:  getOrAddMap(root, "outcomes"),
+		statuses:  getOrAddMap(root, "statuses"),
+		grpcCodes: getOrAddMap(root, "grpc_codes"),
+	}
+}
+
+// getOrAddMap returns the map stored under key, adding it when missing
+func getOrAddMap(root *expvar.Map, key string) *expvar.Map {
+	if m, ok := root.Get(key).(*expvar.Map); ok {
+		return m
+	}
+	m := new(expvar.Map).Init()
+	root.Set(key, m)
+	return m
+}
+
+// Observe increments the counters matching the observation
+func (eo expvarObserver) Observe(o Observation) {
+	eo.outcomes.Add(o.Outcome.String(), 1)
+	if o.Rule != nil {
+		eo.rules.Add(ruleID(o.Mapper, *o.Rule), 1)
+	}
+	if o.Status != 0 {
+		eo.statuses.Add(strconv.Itoa(o.Status), 1)
+	}
+	if errWithCode, ok := o.Mapped.(ErrorWithGRPCStatusProvider); ok {
+		eo.grpcCodes.Add(errWithCode.GRPCCode().String(), 1)
+	}
+}
+
+// ruleID identifies a rule by its mapper, its index and what it matches, leaving out its target
+// which can be built from the matched error and would make the number of counters grow without bound
+// the rules stored in a map, e.g.: by a HashableMapper, have no index and keep their target instead,
+// which tells apart keys with the same text
+func ruleID(mapper int, r Rule) string {
+	id := "mapper #" + strconv.Itoa(mapper)
+	if r.Index >= 0 {
+		id += " rule #" + strconv.Itoa(r.Index)
+	}
+	switch {
+	case r.Format != "":
+		id += " format " + strconv.Quote(r.Format)
+	case r.Key != nil:
+		id += " error " + strconv.Quote(r.Key.Error())
+	default:
+		return id + " " + r.Matcher
+	}
+	if r.Index < 0 {
+		id += " -> " + targetID(r)
+	}
+	return id
+}
+
+// targetID describes the target of a rule by its text and its http status, if any
+func targetID(r Rule) string {
+	if r.Target == nil {
+		return r.Outcome.String()
+	}
+	id := strconv.Quote(r.Target.Error())
+	if errWithStatus, ok := r.Target.(ErrorWithStatusProvider); ok {
+		id += " " + strconv.Itoa(errWithStatus.Status())
+	}
+	return id
+}
+
+// WithExpvar returns a copy of the MultiErr publishing counters under name with the expvar package
+// see NewExpvarObserver for the list of counters
+func (m MultiErr) WithExpvar(name string) MultiErr {
+	return m.WithObservers(NewExpvarObserver(name))
+}
@@ -0,0 +1,127 @@
+package maperr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iZettle/maperr/v4"
+)
+
+func TestMultiErr_WithExpvar(t *testing.T) {
+	errNoRows := errors.New("no rows")
+	errIgnored := errors.New("ignored")
+
+	errMapper := maperr.NewMultiErr(
+		maperr.NewListMapper().
+			Append(errNoRows, maperr.WithStatus("user not found", http.StatusNotFound)),
+		maperr.NewIgnoreListMapper().
+			Append(errIgnored),
+	).WithExpvar("maperr_test_users")
+
+	errMapper.MappedWithStatus(errNoRows, maperr.WithStatusInternalServerError)
+	errMapper.MappedWithStatus(errNoRows, maperr.WithStatusInternalServerError)
+	errMapper.MappedWithStatus(errIgnored, maperr.WithStatusInternalServerError)
+	errMapper.MappedWithStatus(errors.New("boom"), maperr.WithStatusInternalServerError)
+	errMapper.MappedWithGRPCStatus(errNoRows, maperr.WithCodeInternal)
+	errMapper.Mapped(errors.New("boom"), nil)
+
+	// a second MultiErr with the same name shares the counters
+	maperr.NewMultiErr().WithExpvar("maperr_test_users").Mapped(errors.New("boom"), errors.New("default"))
+
+	published := expvar.Get("maperr_test_users")
+	require.NotNil(t, published)
+
+	var counters map[string]map[string]int
+	require.NoError(t, json.Unmarshal([]byte(published.String()), &counters))
+
+	assert.Equal(t, map[string]map[string]int{
+		"rules": {
+			`mapper #0 rule #0 error "no rows"`: 3,
+			`mapper #1 rule #0 error "ignored"`: 1,
+		},
+		"outcomes": {
+			"mapped":         3,
+			"ignored":        1,
+			"defaulted":      2,
+			"passed through": 1,
+		},
+		"statuses": {
+			"404": 2,
+			"500": 1,
+		},
+		"grpc_codes": {
+			"NotFound": 1,
+		},
+	}, counters)
+}
+
+func TestMultiErr_WithExpvar_RulesByIdentity(t *testing.T) {
+	errMapper := maperr.NewMultiErr(
+		maperr.NewListMapper().
+			Appendf("user %s is locked", maperr.Template("user %s is locked", maperr.SelectArgs(0))),
+	).WithExpvar("maperr_test_identity")
+
+	errMapper.Mapped(maperr.Errorf("user %s is locked", "alice"), nil)
+	errMapper.Mapped(maperr.Errorf("user %s is locked", "bob"), nil)
+
+	var counters map[string]map[string]int
+	require.NoError(t, json.Unmarshal([]byte(expvar.Get("maperr_test_identity").String()), &counters))
+	assert.Equal(t, map[string]int{`mapper #0 rule #0 format "user %s is locked"`: 2}, counters["rules"])
+}
+
+func TestMultiErr_WithExpvar_HashableKeysWithSameText(t *testing.T) {
+	errUserNotFound := errors.New("not found")
+	errUserDeleted := errors.New("not found")
+
+	errMapper := maperr.NewMultiErr(
+		maperr.NewHashableMapper().
+			Append(errUserNotFound, maperr.WithStatus("user not found", http.StatusNotFound)).
+			Append(errUserDeleted, maperr.WithStatus("user deleted", http.StatusGone)),
+	).WithExpvar("maperr_test_same_text")
+
+	errMapper.Mapped(errUserNotFound, nil)
+	errMapper.Mapped(errUserDeleted, nil)
+	errMapper.Mapped(errUserDeleted, nil)
+
+	var counters map[string]map[string]int
+	require.NoError(t, json.Unmarshal([]byte(expvar.Get("maperr_test_same_text").String()), &counters))
+	assert.Equal(t, map[string]int{
+		`mapper #0 error "not found" -> "user not found" 404`: 1,
+		`mapper #0 error "not found" -> "user deleted" 410`:   2,
+	}, counters["rules"])
+}
+
+func TestNewExpvarObserver_NameCollision(t *testing.T) {
+	// "cmdline" is published by the expvar package itself
+	observer := maperr.NewExpvarObserver("cmdline")
+	assert.NotPanics(t, func() {
+		maperr.NewMultiErr().WithObservers(observer).Mapped(errors.New("boom"), errors.New("default"))
+	})
+	_, isMap := expvar.Get("cmdline").(*expvar.Map)
+	assert.False(t, isMap)
+}
+
+func TestNewExpvarObserver_Concurrent(t *testing.T) {
+	const workers = 8
+
+	var wg sync.WaitGroup
+	for k := 0; k < workers; k++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			maperr.NewMultiErr().WithExpvar("maperr_test_concurrent").Mapped(errors.New("boom"), nil)
+		}()
+	}
+	wg.Wait()
+
+	var counters map[string]map[string]int
+	require.NoError(t, json.Unmarshal([]byte(expvar.Get("maperr_test_concurrent").String()), &counters))
+	assert.Equal(t, workers, counters["outcomes"]["passed through"])
+}
@@ -0,0 +1,55 @@
+package maperr
+
+// FuncMapper is a Mapper which matches errors using functions,
+// useful when the error can not be expressed as a value
+// e.g.: any error with a Timeout() method returning true
+type FuncMapper struct {
+	funcs []func(error) (error, bool)
+}
+
+// NewFuncMapper return a new FuncMapper
+func NewFuncMapper() FuncMapper {
+	return FuncMapper{}
+}
+
+// Append maps any error for which the predicate returns true to match
+func (fm FuncMapper) Append(predicate func(error) bool, match error) FuncMapper {
+	return fm.AppendFunc(func(err error) (error, bool) {
+		if predicate(err) {
+			return match, true
+		}
+		return nil, false
+	})
+}
+
+// AppendFunc maps any error for which transform returns true
+// to the error returned by transform
+func (fm FuncMapper) AppendFunc(transform func(error) (error, bool)) FuncMapper {
+	fm.funcs = append(fm.funcs, transform)
+	return fm
+}
+
+// MapErr an error to the error returned by the first matching function
+// every error held by err is compared, see walk for the order in which they are compared
+func (fm FuncMapper) MapErr(err error) MapResult {
+	return mapRules(err, fm)
+}
+
+// ExplainErr lists the comparisons made by MapErr
+func (fm FuncMapper) ExplainErr(err error) []Attempt {
+	return explainRules(err, fm)
+}
+
+func (fm FuncMapper) matchRule(err error) Attempt {
+	for k := range fm.funcs {
+		if mapped, ok := fm.funcs[k](err); ok {
+			return matched(err, Rule{
+				Index:   k,
+				Matcher: "func",
+				Target:  mapped,
+				Outcome: OutcomeMapped,
+			})
+		}
+	}
+	return notMatched(err, "no function matched")
+}
@@ -0,0 +1,103 @@
+package maperr_test
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/multierr"
+
+	"github.com/iZettle/maperr/v4"
+)
+
+func TestFuncMapper_Mapped(t *testing.T) {
+	errTimeout := errors.New("timeout")
+	errURL := errors.New("bad url")
+
+	isTimeout := func(err error) bool {
+		var netErr net.Error
+		return errors.As(err, &netErr) && netErr.Timeout()
+	}
+
+	toURLError := func(err error) (error, bool) {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return maperr.Errorf("could not %s %s", urlErr.Op, urlErr.URL), true
+		}
+		return nil, false
+	}
+
+	errMapper := maperr.NewMultiErr(
+		maperr.NewFuncMapper().
+			Append(isTimeout, errTimeout).
+			AppendFunc(toURLError).
+			Append(func(err error) bool { return err.Error() == "never" }, errURL),
+	)
+
+	tests := []struct {
+		name        string
+		givenError  error
+		expectedErr string
+	}{
+		{
+			name:        "error is not matched",
+			givenError:  errors.New("random error"),
+			expectedErr: "random error",
+		},
+		{
+			name:        "error is matched by predicate",
+			givenError:  &net.DNSError{Err: "no such host", IsTimeout: true},
+			expectedErr: "lookup : no such host; timeout",
+		},
+		{
+			name:        "error is matched by transformer",
+			givenError:  &url.Error{Op: "Get", URL: "http://foo", Err: errors.New("boom")},
+			expectedErr: "Get \"http://foo\": boom; could not Get http://foo",
+		},
+		{
+			name: "newest error in the chain wins",
+			givenError: multierr.Combine(
+				&url.Error{Op: "Get", URL: "http://foo", Err: errors.New("boom")},
+				&net.DNSError{Err: "no such host", IsTimeout: true},
+			),
+			expectedErr: "Get \"http://foo\": boom; lookup : no such host; timeout",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actualErr := errMapper.Mapped(test.givenError, nil)
+			assert.EqualError(t, actualErr, test.expectedErr)
+		})
+	}
+}
+
+func TestFuncMapper_SamePrecedenceAsListMapper(t *testing.T) {
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+	errFromFirst := errors.New("from first")
+	errFromSecond := errors.New("from second")
+
+	funcMapper := maperr.NewFuncMapper().
+		Append(func(err error) bool { return errors.Is(err, errFirst) }, errFromFirst).
+		Append(func(err error) bool { return errors.Is(err, errSecond) }, errFromSecond)
+	listMapper := maperr.NewListMapper().
+		Append(errFirst, errFromFirst).
+		Append(errSecond, errFromSecond)
+
+	for _, given := range []error{maperr.Combine(errFirst, errSecond), maperr.CombineJoin(errFirst, errSecond)} {
+		assert.EqualError(t, funcMapper.MapErr(given).Last(), "from second")
+		assert.EqualError(t, listMapper.MapErr(given).Last(), "from second")
+	}
+}
+
+func TestMapperFunc(t *testing.T) {
+	errMapped := errors.New("mapped")
+	mapper := maperr.MapperFunc(func(err error) maperr.MapResult {
+		return maperr.NewAppendStrategy(err, errMapped)
+	})
+
+	actualErr := maperr.NewMultiErr(mapper).Mapped(errors.New("original"), nil)
+	assert.EqualError(t, actualErr, "original; mapped")
+}
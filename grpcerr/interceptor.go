@@ -0,0 +1,89 @@
+// Package grpcerr provides gRPC server interceptors which map the errors
+// returned by the handlers to a gRPC status using a maperr.MultiErr
+package grpcerr
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/iZettle/maperr/v4"
+)
+
+// LogFunc is called with the cause of every error returned by a handler which is not ignored,
+// along with the status returned to the client
+type LogFunc func(ctx context.Context, fullMethod string, cause error, st *status.Status)
+
+// Option allows to configure the interceptors
+type Option func(*interceptor)
+
+// WithLogFunc sets the function called with the cause of every error which is not ignored
+func WithLogFunc(logFunc LogFunc) Option {
+	return func(i *interceptor) {
+		i.logFunc = logFunc
+	}
+}
+
+type interceptor struct {
+	mapper     maperr.MultiErr
+	defaultErr error
+	logFunc    LogFunc
+}
+
+func newInterceptor(mapper maperr.MultiErr, defaultErr error, opts ...Option) interceptor {
+	if defaultErr == nil {
+		defaultErr = maperr.WithCodeInternal
+	}
+	i := interceptor{
+		mapper:     mapper.WithStatusRequired(),
+		defaultErr: defaultErr,
+	}
+	for _, opt := range opts {
+		opt(&i)
+	}
+	return i
+}
+
+// UnaryServerInterceptor returns a grpc.UnaryServerInterceptor which maps the errors returned by the handlers
+//
+// errors ignored by the mapper are dropped and the response of the handler is returned as is,
+// errors which are not mapped but already carry a gRPC status, e.g.: created with status.Error, are returned as is,
+// other errors which are not mapped to a gRPC status, are mapped to defaultErr
+// errors of canceled calls are mapped to codes.Canceled, see maperr.MultiErr.WithCanceledErr
+// defaultErr == nil is the same as maperr.WithCodeInternal
+func UnaryServerInterceptor(mapper maperr.MultiErr, defaultErr error, opts ...Option) grpc.UnaryServerInterceptor {
+	i := newInterceptor(mapper, defaultErr, opts...)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		return resp, i.mapErr(ctx, info.FullMethod, err)
+	}
+}
+
+// StreamServerInterceptor returns a grpc.StreamServerInterceptor which maps the errors returned by the handlers
+// errors are mapped the same way as UnaryServerInterceptor does
+func StreamServerInterceptor(mapper maperr.MultiErr, defaultErr error, opts ...Option) grpc.StreamServerInterceptor {
+	i := newInterceptor(mapper, defaultErr, opts...)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return i.mapErr(ss.Context(), info.FullMethod, handler(srv, ss))
+	}
+}
+
+// mapErr maps the error returned by a handler to an error with a gRPC status, using the context of the call
+func (i interceptor) mapErr(ctx context.Context, fullMethod string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	mapped := i.mapper.MappedWithGRPCStatusContext(ctx, err, i.defaultErr)
+	if mapped == nil {
+		// the error was ignored
+		return nil
+	}
+
+	st := mapped.GRPCStatus()
+	if i.logFunc != nil {
+		i.logFunc(ctx, fullMethod, mapped.Unwrap(), st)
+	}
+	return st.Err()
+}
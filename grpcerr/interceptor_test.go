@@ -0,0 +1,282 @@
+package grpcerr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/iZettle/maperr/v4"
+	"github.com/iZettle/maperr/v4/grpcerr"
+)
+
+var (
+	errNotFound = errors.New("not found")
+	errConflict = errors.New("conflict")
+	errNoCode   = errors.New("no code")
+	errIgnored  = errors.New("ignored")
+)
+
+// healthServer returns the error registered for the requested service
+type healthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	errs map[string]error
+}
+
+func (hs healthServer) Check(_ context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, hs.errs[req.Service]
+}
+
+func (hs healthServer) Watch(req *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	return hs.errs[req.Service]
+}
+
+type logged struct {
+	fullMethod string
+	cause      error
+	code       codes.Code
+}
+
+func newClient(t *testing.T, logs *[]logged) grpc_health_v1.HealthClient {
+	errMapper := maperr.NewMultiErr(
+		maperr.NewHashableMapper().
+			Append(errNotFound, maperr.WithCode("service not found", codes.NotFound)).
+			Append(errConflict, maperr.WithStatus("service conflict", http.StatusConflict)).
+			Append(errNoCode, errors.New("mapped without code")),
+		maperr.NewIgnoreListMapper().
+			Append(errIgnored),
+	)
+	logFunc := grpcerr.WithLogFunc(func(ctx context.Context, fullMethod string, cause error, st *status.Status) {
+		*logs = append(*logs, logged{fullMethod: fullMethod, cause: cause, code: st.Code()})
+	})
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(grpcerr.UnaryServerInterceptor(errMapper, maperr.WithCodeInternal, logFunc)),
+		grpc.StreamInterceptor(grpcerr.StreamServerInterceptor(errMapper, nil, logFunc)),
+	)
+	grpc_health_v1.RegisterHealthServer(server, healthServer{
+		errs: map[string]error{
+			"not-found": fmt.Errorf("lookup: %w", errNotFound),
+			"conflict":  errConflict,
+			"no-code":   errNoCode,
+			"ignored":   maperr.Append(errors.New("first"), errIgnored),
+			"unknown":   errors.New("boom"),
+			"status":    status.Error(codes.InvalidArgument, "bad service name"),
+		},
+	})
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return grpc_health_v1.NewHealthClient(conn)
+}
+
+var interceptorTests = []struct {
+	name          string
+	service       string
+	expectedCode  codes.Code
+	expectedMsg   string
+	expectedCause string
+}{
+	{
+		name:         "no error",
+		service:      "",
+		expectedCode: codes.OK,
+	},
+	{
+		name:          "error mapped to a gRPC code",
+		service:       "not-found",
+		expectedCode:  codes.NotFound,
+		expectedMsg:   "service not found",
+		expectedCause: "lookup: not found",
+	},
+	{
+		name:          "error mapped to an http status",
+		service:       "conflict",
+		expectedCode:  codes.AlreadyExists,
+		expectedMsg:   "service conflict",
+		expectedCause: "conflict",
+	},
+	{
+		name:          "error mapped without a code uses the default error",
+		service:       "no-code",
+		expectedCode:  codes.Internal,
+		expectedMsg:   "Internal",
+		expectedCause: "no code",
+	},
+	{
+		name:         "error ignored",
+		service:      "ignored",
+		expectedCode: codes.OK,
+	},
+	{
+		name:          "error not mapped uses the default error",
+		service:       "unknown",
+		expectedCode:  codes.Internal,
+		expectedMsg:   "Internal",
+		expectedCause: "boom",
+	},
+	{
+		name:          "error not mapped keeps its gRPC status",
+		service:       "status",
+		expectedCode:  codes.InvalidArgument,
+		expectedMsg:   "bad service name",
+		expectedCause: "rpc error: code = InvalidArgument desc = bad service name",
+	},
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	for _, test := range interceptorTests {
+		t.Run(test.name, func(t *testing.T) {
+			var logs []logged
+			client := newClient(t, &logs)
+
+			_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: test.service})
+			assertStatus(t, err, test.expectedCode, test.expectedMsg)
+			assertLogs(t, logs, "/grpc.health.v1.Health/Check", test.expectedCode, test.expectedCause)
+		})
+	}
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	for _, test := range interceptorTests {
+		t.Run(test.name, func(t *testing.T) {
+			var logs []logged
+			client := newClient(t, &logs)
+
+			stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: test.service})
+			require.NoError(t, err)
+			_, err = stream.Recv()
+			if test.expectedCode == codes.OK {
+				assert.ErrorIs(t, err, io.EOF)
+			} else {
+				assertStatus(t, err, test.expectedCode, test.expectedMsg)
+			}
+			assertLogs(t, logs, "/grpc.health.v1.Health/Watch", test.expectedCode, test.expectedCause)
+		})
+	}
+}
+
+func assertStatus(t *testing.T, err error, expectedCode codes.Code, expectedMsg string) {
+	t.Helper()
+	st := status.Convert(err)
+	assert.Equal(t, expectedCode, st.Code())
+	if expectedCode != codes.OK {
+		assert.Equal(t, expectedMsg, st.Message())
+	}
+}
+
+func assertLogs(t *testing.T, logs []logged, fullMethod string, expectedCode codes.Code, expectedCause string) {
+	t.Helper()
+	if expectedCause == "" {
+		assert.Empty(t, logs)
+		return
+	}
+	if assert.Len(t, logs, 1) {
+		assert.Equal(t, fullMethod, logs[0].fullMethod)
+		assert.EqualError(t, logs[0].cause, expectedCause)
+		assert.Equal(t, expectedCode, logs[0].code)
+	}
+}
+
+func TestUnaryServerInterceptor_MapsOnce(t *testing.T) {
+	tests := []struct {
+		name            string
+		givenErr        error
+		expectedOutcome maperr.Outcome
+		expectedCode    codes.Code
+	}{
+		{
+			name:            "error mapped without a code",
+			givenErr:        errNoCode,
+			expectedOutcome: maperr.OutcomeDefaulted,
+			expectedCode:    codes.Internal,
+		},
+		{
+			name:            "error ignored",
+			givenErr:        errIgnored,
+			expectedOutcome: maperr.OutcomeIgnored,
+			expectedCode:    codes.OK,
+		},
+		{
+			name:            "error not mapped",
+			givenErr:        errors.New("boom"),
+			expectedOutcome: maperr.OutcomeDefaulted,
+			expectedCode:    codes.Internal,
+		},
+		{
+			name:            "error not mapped with a gRPC status",
+			givenErr:        status.Error(codes.InvalidArgument, "bad service name"),
+			expectedOutcome: maperr.OutcomePassedThrough,
+			expectedCode:    codes.InvalidArgument,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var observed []maperr.Observation
+			errMapper := maperr.NewMultiErr(
+				maperr.NewHashableMapper().
+					Append(errNoCode, errors.New("mapped without code")),
+				maperr.NewIgnoreListMapper().
+					Append(errIgnored),
+			).WithObservers(maperr.ObserverFunc(func(o maperr.Observation) {
+				observed = append(observed, o)
+			}))
+
+			interceptor := grpcerr.UnaryServerInterceptor(errMapper, nil)
+			_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test/Method"},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					return nil, test.givenErr
+				})
+
+			assert.Equal(t, test.expectedCode, status.Code(err))
+			if assert.Len(t, observed, 1) {
+				assert.Equal(t, test.expectedOutcome, observed[0].Outcome)
+				assert.Equal(t, test.expectedCode, observed[0].GRPCCode)
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptor_CanceledContext(t *testing.T) {
+	errMapper := maperr.NewMultiErr(
+		maperr.NewHashableMapper().
+			Append(errNotFound, maperr.WithCode("service not found", codes.NotFound)),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	interceptor := grpcerr.UnaryServerInterceptor(errMapper, nil)
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test/Method"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, errNotFound
+		})
+
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
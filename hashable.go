@@ -1,9 +1,7 @@
 package maperr
 
 import (
-	"errors"
-
-	"go.uber.org/multierr"
+	"sort"
 )
 
 // HashableMapper simple implementation of Mapper which only works
@@ -23,30 +21,65 @@ func (hm HashableMapper) Append(err, match error) HashableMapper {
 	return hm
 }
 
-// mapErr an error to another error
-func (hm HashableMapper) mapErr(err error) mapResult {
-	errorsToMap := []error{
-		err,
+// MapErr an error to another error
+// every error held by err is compared, see walk for the order in which they are compared
+func (hm HashableMapper) MapErr(err error) MapResult {
+	return mapRules(err, hm)
+}
+
+// ExplainErr lists the comparisons made by MapErr
+func (hm HashableMapper) ExplainErr(err error) []Attempt {
+	return explainRules(err, hm)
+}
+
+// Rules lists the rules of the mapper, sorted by the text of their key as a map is not ordered
+func (hm HashableMapper) Rules() []Rule {
+	rules := make([]Rule, 0, len(hm))
+	for key := range hm {
+		rules = append(rules, hm.rule(key))
+	}
+	sortByKey(rules)
+	return rules
+}
+
+// sortByKey sorts rules by the text of their key
+func sortByKey(rules []Rule) {
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].Key.Error() < rules[j].Key.Error()
+	})
+}
+
+func (hm HashableMapper) matchRule(err error) Attempt {
+	key := hm.tryMakeHashable(err)
+	if !isHashable(key) {
+		return notMatched(err, "error is not hashable")
 	}
-	if errList := multierr.Errors(err); len(errList) > 0 {
-		errorsToMap = errList
+	if _, ok := hm[key]; !ok {
+		return notMatched(err, "no rule matched")
 	}
+	return matched(err, hm.rule(key))
+}
 
-	for i := len(errorsToMap) - 1; i >= 0; i-- {
-		key := hm.tryMakeHashable(errorsToMap[i])
-		mapped, ok := hm[key]
-		if ok {
-			return newAppendStrategy(err, mapped)
-		}
+// rule describes the association of the hashable key
+func (hm HashableMapper) rule(key error) Rule {
+	return Rule{
+		Index:   -1,
+		Key:     key,
+		Target:  hm[key],
+		Outcome: OutcomeMapped,
 	}
-	return nil
 }
 
 func (hm HashableMapper) tryMakeHashable(err error) error {
+	return hashableKey(err)
+}
+
+// hashableKey returns the error compared by identity for err,
+// the underlying error of a formatted error
+func hashableKey(err error) error {
 	key := err
 
-	var ferr formattedError
-	if errors.As(err, &ferr) {
+	if ferr, ok := asChain[formattedError](err); ok {
 		key = ferr.Hashable()
 	}
 
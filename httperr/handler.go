@@ -0,0 +1,105 @@
+// Package httperr provides an adapter for http handlers returning an error,
+// which maps the error to an http response using a maperr.MultiErr
+package httperr
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/iZettle/maperr/v4"
+)
+
+// HandlerFunc is an http handler which returns an error instead of writing it
+// the handler should not write to the http.ResponseWriter when returning an error
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// LogFunc is called with the cause of every error returned by a handler which is not ignored,
+// along with the mapped error written in the response
+type LogFunc func(r *http.Request, cause error, mapped maperr.ErrorWithStatusProvider)
+
+// RenderFunc writes a mapped error to the response
+type RenderFunc func(w http.ResponseWriter, r *http.Request, mapped maperr.ErrorWithStatusProvider)
+
+// Option allows to configure an Adapter
+type Option func(*Adapter)
+
+// WithLogFunc sets the function called with the cause of every error which is not ignored
+func WithLogFunc(logFunc LogFunc) Option {
+	return func(a *Adapter) {
+		a.logFunc = logFunc
+	}
+}
+
+// WithRenderFunc sets the function used to write the mapped error to the response
+// WriteText is used by default
+func WithRenderFunc(renderFunc RenderFunc) Option {
+	return func(a *Adapter) {
+		a.renderFunc = renderFunc
+	}
+}
+
+// Adapter turns a HandlerFunc into an http.Handler
+type Adapter struct {
+	mapper     maperr.MultiErr
+	defaultErr error
+	logFunc    LogFunc
+	renderFunc RenderFunc
+}
+
+// NewAdapter return a new Adapter which maps the errors returned by the handlers using mapper
+//
+// errors ignored by the mapper are dropped, leaving the response untouched
+// errors which are not mapped to an http status, are mapped to defaultErr
+// errors of canceled requests are mapped to a 499 Client Closed Request, see maperr.MultiErr.WithCanceledErr
+// defaultErr == nil is the same as maperr.WithStatusInternalServerError
+func NewAdapter(mapper maperr.MultiErr, defaultErr error, opts ...Option) Adapter {
+	if defaultErr == nil {
+		defaultErr = maperr.WithStatusInternalServerError
+	}
+	a := Adapter{
+		mapper:     mapper.WithStatusRequired(),
+		defaultErr: defaultErr,
+		renderFunc: WriteText,
+	}
+	for _, opt := range opts {
+		opt(&a)
+	}
+	return a
+}
+
+// Handle returns an http.Handler which calls handler and writes the mapped error
+func (a Adapter) Handle(handler HandlerFunc) http.Handler {
+	return a.HandleFunc(handler)
+}
+
+// HandleFunc returns an http.HandlerFunc which calls handler and writes the mapped error
+func (a Adapter) HandleFunc(handler HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mapped := a.mapErr(r.Context(), handler(w, r))
+		if mapped == nil {
+			return
+		}
+
+		if a.logFunc != nil {
+			a.logFunc(r, mapped.Unwrap(), mapped)
+		}
+		a.renderFunc(w, r, mapped)
+	}
+}
+
+// mapErr maps the error returned by a handler to an error with an http status, using the context of the request
+// returns nil when there was no error or when it is ignored
+func (a Adapter) mapErr(ctx context.Context, err error) maperr.ErrorWithStatusProvider {
+	return a.mapper.MappedWithStatusContext(ctx, err, a.defaultErr)
+}
+
+// WriteText writes the mapped error as plain text
+// the message is prefixed by the code of the error when it has one
+// e.g.: USER_NOT_FOUND: user was not found
+func WriteText(w http.ResponseWriter, _ *http.Request, mapped maperr.ErrorWithStatusProvider) {
+	body := mapped.Error()
+	if code := mapped.Code(); code != "" {
+		body = code + ": " + body
+	}
+	http.Error(w, body, mapped.Status())
+}
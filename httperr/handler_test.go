@@ -0,0 +1,204 @@
+package httperr_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iZettle/maperr/v4"
+	"github.com/iZettle/maperr/v4/httperr"
+)
+
+var (
+	errNotFound = errors.New("not found")
+	errConflict = errors.New("conflict")
+	errNoStatus = errors.New("no status")
+	errIgnored  = errors.New("ignored")
+)
+
+var errMapper = maperr.NewMultiErr(
+	maperr.NewHashableMapper().
+		Append(errNotFound, maperr.WithStatus("user not found", http.StatusNotFound)).
+		Append(errConflict, maperr.WithStatusCode("USER_EXISTS", "user already exists", http.StatusConflict)).
+		Append(errNoStatus, errors.New("mapped without status")),
+	maperr.NewIgnoreListMapper().
+		Append(errIgnored),
+)
+
+func TestAdapter_Handle(t *testing.T) {
+	tests := []struct {
+		name           string
+		handlerErr     error
+		defaultErr     error
+		expectedStatus int
+		expectedBody   string
+		expectedCause  string
+	}{
+		{
+			name:           "no error",
+			handlerErr:     nil,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "ok",
+		},
+		{
+			name:           "error is mapped",
+			handlerErr:     fmt.Errorf("get user: %w", errNotFound),
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "user not found\n",
+			expectedCause:  "get user: not found",
+		},
+		{
+			name:           "error is mapped with a code",
+			handlerErr:     errConflict,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   "USER_EXISTS: user already exists\n",
+			expectedCause:  "conflict",
+		},
+		{
+			name:           "error is ignored",
+			handlerErr:     maperr.Append(errors.New("first"), errIgnored),
+			expectedStatus: http.StatusOK,
+			expectedBody:   "ok",
+		},
+		{
+			name:           "error is not mapped",
+			handlerErr:     errors.New("boom"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Internal Server Error\n",
+			expectedCause:  "boom",
+		},
+		{
+			name:           "error is not mapped with a default error",
+			handlerErr:     errors.New("boom"),
+			defaultErr:     maperr.WithStatusBadRequest,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Bad Request\n",
+			expectedCause:  "boom",
+		},
+		{
+			name:           "error is mapped without status",
+			handlerErr:     errNoStatus,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Internal Server Error\n",
+			expectedCause:  "no status",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var causes []error
+			adapter := httperr.NewAdapter(errMapper, test.defaultErr,
+				httperr.WithLogFunc(func(r *http.Request, cause error, mapped maperr.ErrorWithStatusProvider) {
+					causes = append(causes, cause)
+				}))
+
+			handler := adapter.Handle(func(w http.ResponseWriter, r *http.Request) error {
+				// ignored errors are returned after the response has been written
+				if test.handlerErr == nil || errors.Is(test.handlerErr, errIgnored) {
+					_, _ = w.Write([]byte("ok"))
+				}
+				return test.handlerErr
+			})
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+			assert.Equal(t, test.expectedStatus, rec.Code)
+			assert.Equal(t, test.expectedBody, rec.Body.String())
+			if test.expectedCause == "" {
+				assert.Empty(t, causes)
+			} else if assert.Len(t, causes, 1) {
+				assert.EqualError(t, causes[0], test.expectedCause)
+			}
+		})
+	}
+}
+
+func TestAdapter_MapsOnce(t *testing.T) {
+	tests := []struct {
+		name            string
+		handlerErr      error
+		expectedOutcome maperr.Outcome
+		expectedStatus  int
+	}{
+		{
+			name:            "error mapped without status",
+			handlerErr:      errNoStatus,
+			expectedOutcome: maperr.OutcomeDefaulted,
+			expectedStatus:  http.StatusInternalServerError,
+		},
+		{
+			name:            "error ignored",
+			handlerErr:      errIgnored,
+			expectedOutcome: maperr.OutcomeIgnored,
+			expectedStatus:  http.StatusOK,
+		},
+		{
+			name:            "error not mapped",
+			handlerErr:      errors.New("boom"),
+			expectedOutcome: maperr.OutcomeDefaulted,
+			expectedStatus:  http.StatusInternalServerError,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var observed []maperr.Observation
+			adapter := httperr.NewAdapter(errMapper.WithObservers(maperr.ObserverFunc(func(o maperr.Observation) {
+				observed = append(observed, o)
+			})), nil)
+
+			handler := adapter.HandleFunc(func(w http.ResponseWriter, r *http.Request) error {
+				return test.handlerErr
+			})
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, test.expectedStatus, rec.Code)
+			if assert.Len(t, observed, 1) {
+				assert.Equal(t, test.expectedOutcome, observed[0].Outcome)
+			}
+		})
+	}
+}
+
+func TestAdapter_CanceledRequest(t *testing.T) {
+	var logged []string
+	adapter := httperr.NewAdapter(errMapper, nil,
+		httperr.WithLogFunc(func(r *http.Request, cause error, mapped maperr.ErrorWithStatusProvider) {
+			logged = append(logged, cause.Error())
+		}))
+
+	handler := adapter.HandleFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return fmt.Errorf("get user: %w", errNotFound)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+
+	assert.Equal(t, maperr.StatusClientClosedRequest, rec.Code)
+	assert.Equal(t, []string{"get user: not found"}, logged)
+}
+
+func TestAdapter_WithRenderFunc(t *testing.T) {
+	adapter := httperr.NewAdapter(errMapper, nil,
+		httperr.WithRenderFunc(func(w http.ResponseWriter, r *http.Request, mapped maperr.ErrorWithStatusProvider) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(mapped.Status())
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": mapped.Error()})
+		}))
+
+	rec := httptest.NewRecorder()
+	adapter.HandleFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return errNotFound
+	}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.JSONEq(t, `{"error":"user not found"}`, rec.Body.String())
+}
@@ -0,0 +1,85 @@
+package httperr
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/iZettle/maperr/v4"
+)
+
+// ProblemContentType is the media type of a problem details document
+const ProblemContentType = "application/problem+json"
+
+// defaultProblemType is the problem type used when none was set
+const defaultProblemType = "about:blank"
+
+// Problem is a problem details document as defined by RFC 9457 (previously RFC 7807)
+// Code is the machine-readable code of the error, rendered as the "code" member when set
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Code       string
+	Extensions map[string]interface{}
+}
+
+// NewProblem returns the problem details document describing a mapped error
+// the type and the extension members are taken from the maperr.WithStatus options
+func NewProblem(r *http.Request, mapped maperr.ErrorWithStatusProvider) Problem {
+	problem := Problem{
+		Type:     defaultProblemType,
+		Title:    http.StatusText(mapped.Status()),
+		Status:   mapped.Status(),
+		Detail:   mapped.Error(),
+		Instance: r.URL.Path,
+		Code:     mapped.Code(),
+	}
+
+	var provider maperr.ProblemProvider
+	if errors.As(mapped, &provider) {
+		if problemType := provider.ProblemType(); problemType != "" {
+			problem.Type = problemType
+		}
+		problem.Extensions = provider.ProblemExtensions()
+	}
+
+	return problem
+}
+
+// MarshalJSON encodes the problem with the extension members at the top level,
+// extension members can not override the members defined by the RFC
+func (p Problem) MarshalJSON() ([]byte, error) {
+	members := make(map[string]interface{}, len(p.Extensions)+6)
+	for name, value := range p.Extensions {
+		members[name] = value
+	}
+	members["type"] = p.Type
+	members["title"] = p.Title
+	members["status"] = p.Status
+	members["detail"] = p.Detail
+	members["instance"] = p.Instance
+	if p.Code != "" {
+		members["code"] = p.Code
+	}
+
+	return json.Marshal(members)
+}
+
+// WriteProblem writes the mapped error as a problem details document
+// it can be used with WithRenderFunc
+func WriteProblem(w http.ResponseWriter, r *http.Request, mapped maperr.ErrorWithStatusProvider) {
+	body, err := json.Marshal(NewProblem(r, mapped))
+	if err != nil {
+		// extension members could not be encoded, fallback on a plain text response
+		WriteText(w, r, mapped)
+		return
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(mapped.Status())
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,89 @@
+package httperr_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iZettle/maperr/v4"
+	"github.com/iZettle/maperr/v4/httperr"
+)
+
+func TestWriteProblem(t *testing.T) {
+	errOutOfCredit := errors.New("out of credit")
+
+	problemMapper := maperr.NewMultiErr(
+		maperr.NewHashableMapper().
+			Append(errNotFound, maperr.WithStatus("user not found", http.StatusNotFound)).
+			Append(errOutOfCredit, maperr.WithStatusCode("OUT_OF_CREDIT", "your current balance is 30, but that costs 50", http.StatusForbidden,
+				maperr.ProblemType("https://example.com/probs/out-of-credit"),
+				maperr.ProblemExtension("balance", 30),
+				maperr.ProblemExtension("status", "overridden"),
+				maperr.ProblemExtension("code", "overridden"),
+			)),
+	)
+
+	tests := []struct {
+		name           string
+		handlerErr     error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "error mapped without problem details",
+			handlerErr:     errNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"type": "about:blank",
+				"title": "Not Found",
+				"status": 404,
+				"detail": "user not found",
+				"instance": "/accounts/12345"
+			}`,
+		},
+		{
+			name:           "error mapped with code, problem type and extension members",
+			handlerErr:     errOutOfCredit,
+			expectedStatus: http.StatusForbidden,
+			expectedBody: `{
+				"type": "https://example.com/probs/out-of-credit",
+				"title": "Forbidden",
+				"status": 403,
+				"detail": "your current balance is 30, but that costs 50",
+				"instance": "/accounts/12345",
+				"code": "OUT_OF_CREDIT",
+				"balance": 30
+			}`,
+		},
+		{
+			name:           "error not mapped",
+			handlerErr:     errors.New("boom"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"type": "about:blank",
+				"title": "Internal Server Error",
+				"status": 500,
+				"detail": "Internal Server Error",
+				"instance": "/accounts/12345"
+			}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			adapter := httperr.NewAdapter(problemMapper, maperr.WithStatusInternalServerError,
+				httperr.WithRenderFunc(httperr.WriteProblem))
+
+			rec := httptest.NewRecorder()
+			adapter.HandleFunc(func(w http.ResponseWriter, r *http.Request) error {
+				return test.handlerErr
+			}).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/accounts/12345?dryRun=true", nil))
+
+			assert.Equal(t, test.expectedStatus, rec.Code)
+			assert.Equal(t, httperr.ProblemContentType, rec.Header().Get("Content-Type"))
+			assert.JSONEq(t, test.expectedBody, rec.Body.String())
+		})
+	}
+}
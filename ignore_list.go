@@ -1,9 +1,5 @@
 package maperr
 
-import (
-	"go.uber.org/multierr"
-)
-
 // IgnoreListMapper is a Mapper that allow to specify a list of error that we
 // want to ignore
 type IgnoreListMapper struct {
@@ -17,7 +13,7 @@ func NewIgnoreListMapper() IgnoreListMapper {
 
 // Appendf appends a formatted error that we want to ignore
 func (lm IgnoreListMapper) Appendf(format string) IgnoreListMapper {
-	return lm.Append(Errorf(format))
+	return lm.Append(newUnformattedError(format))
 }
 
 // Append appends an error that we want to ignore
@@ -26,52 +22,42 @@ func (lm IgnoreListMapper) Append(err error) IgnoreListMapper {
 	return lm
 }
 
-// mapErr an error to an ignore strategy
-func (lm IgnoreListMapper) mapErr(err error) mapResult {
-	errorsToMap := []error{
-		err,
-	}
-	if errList := multierr.Errors(err); len(errList) > 0 {
-		errorsToMap = errList
-	}
-
-	for i := len(errorsToMap) - 1; i >= 0; i-- {
-		comparableErr := castError(errorsToMap[i])
-		for k := range lm.list {
-			if comparableErr.Equal(lm.list[k]) {
-				return newIgnoreStrategy(err)
-			}
-		}
-	}
-
-	return nil
+// MapErr an error to an ignore strategy
+// every error held by err is compared, see walk for the order in which they are compared
+func (lm IgnoreListMapper) MapErr(err error) MapResult {
+	return mapRules(err, lm)
 }
 
-// ignoreStrategy holds data for an ignore strategy
-type ignoreStrategy struct {
-	previousErr error
+// ExplainErr lists the comparisons made by MapErr
+func (lm IgnoreListMapper) ExplainErr(err error) []Attempt {
+	return explainRules(err, lm)
 }
 
-// newIgnoreStrategy instantiates a new ignoreStrategy
-func newIgnoreStrategy(previous error) ignoreStrategy {
-	return ignoreStrategy{
-		previousErr: previous,
+// Rules lists the rules of the mapper, in the order they are compared
+func (lm IgnoreListMapper) Rules() []Rule {
+	rules := make([]Rule, len(lm.list))
+	for k := range lm.list {
+		rules[k] = lm.rule(k)
 	}
+	return rules
 }
 
-// previous holds the error that we wanted to ignore
-func (as ignoreStrategy) previous() error {
-	return as.previousErr
-}
-
-// last is defined to implement the interface
-// returns nil since we are always mapping to nil for this strategy
-func (as ignoreStrategy) last() error {
-	return nil
+func (lm IgnoreListMapper) matchRule(err error) Attempt {
+	comparableErr := castError(err)
+	for k := range lm.list {
+		if comparableErr.Equal(lm.list[k]) {
+			return matched(err, lm.rule(k))
+		}
+	}
+	return notMatched(err, "no rule matched")
 }
 
-// apply is defined to implement the interface
-// returns nil since we are always mapping to nil for this strategy
-func (as ignoreStrategy) apply() error {
-	return nil
+// rule describes the ignored error at index k
+func (lm IgnoreListMapper) rule(k int) Rule {
+	return Rule{
+		Index:   k,
+		Key:     lm.list[k],
+		Format:  formatOf(lm.list[k]),
+		Outcome: OutcomeIgnored,
+	}
 }
@@ -1,9 +1,5 @@
 package maperr
 
-import (
-	"go.uber.org/multierr"
-)
-
 // PairErrors holds a pair of errorPairs
 type PairErrors struct {
 	err   Error
@@ -22,7 +18,7 @@ func NewListMapper() ListMapper {
 
 // Appendf append a format to error association
 func (lm ListMapper) Appendf(format string, match error) ListMapper {
-	return lm.Append(Errorf(format), castError(match))
+	return lm.Append(newUnformattedError(format), castError(match))
 }
 
 // Append append an error to error association
@@ -35,52 +31,43 @@ func (lm ListMapper) Append(err, match error) ListMapper {
 	return lm
 }
 
-// mapErr a formatted error to an error
-func (lm ListMapper) mapErr(err error) mapResult {
-	errorsToMap := []error{
-		err,
-	}
-	if errList := multierr.Errors(err); len(errList) > 0 {
-		errorsToMap = errList
-	}
-
-	for i := len(errorsToMap) - 1; i >= 0; i-- {
-		comparableErr := castError(errorsToMap[i])
-		for k := range lm.errorPairs {
-			if comparableErr.Equal(lm.errorPairs[k].err) {
-				return newAppendStrategy(err, lm.errorPairs[k].match)
-			}
-		}
-	}
-
-	return nil
-}
-
-// appendStrategy holds data for an ignore strategy
-type appendStrategy struct {
-	previousErr error
-	lastErr     error
+// MapErr a formatted error to an error
+// every error held by err is compared, see walk for the order in which they are compared
+func (lm ListMapper) MapErr(err error) MapResult {
+	return mapRules(err, lm)
 }
 
-// newAppendStrategy instantiates a new appendStrategy
-func newAppendStrategy(previous, last error) appendStrategy {
-	return appendStrategy{previousErr: previous, lastErr: last}
+// ExplainErr lists the comparisons made by MapErr
+func (lm ListMapper) ExplainErr(err error) []Attempt {
+	return explainRules(err, lm)
 }
 
-// previous returns the error that we want to append to
-func (as appendStrategy) previous() error {
-	return as.previousErr
+// Rules lists the rules of the mapper, in the order they are compared
+func (lm ListMapper) Rules() []Rule {
+	rules := make([]Rule, len(lm.errorPairs))
+	for k := range lm.errorPairs {
+		rules[k] = lm.rule(k)
+	}
+	return rules
 }
 
-// last returns the error that we are appending
-func (as appendStrategy) last() error {
-	return as.lastErr
+func (lm ListMapper) matchRule(err error) Attempt {
+	comparableErr := castError(err)
+	for k := range lm.errorPairs {
+		if comparableErr.Equal(lm.errorPairs[k].err) {
+			return matched(err, lm.rule(k))
+		}
+	}
+	return notMatched(err, "no rule matched")
 }
 
-// apply the append strategy by appending previousErr to lastErr
-func (as appendStrategy) apply() error {
-	if as.lastErr == nil {
-		return nil
+// rule describes the pair of errors at index k
+func (lm ListMapper) rule(k int) Rule {
+	return Rule{
+		Index:   k,
+		Key:     lm.errorPairs[k].err,
+		Format:  formatOf(lm.errorPairs[k].err),
+		Target:  lm.errorPairs[k].match,
+		Outcome: OutcomeMapped,
 	}
-	return Append(as.previousErr, as.lastErr)
 }
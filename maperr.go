@@ -1,38 +1,103 @@
 package maperr
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
-	"go.uber.org/multierr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-// mapResult is an interface that defines the result of a mapErr operation
-type mapResult interface {
-	previous() error
-	last() error
-	apply() error
+// Outcome describes what a Mapper decided to do with an error
+type Outcome int
+
+// Outcomes of a MapErr operation
+const (
+	// OutcomePassedThrough the error was not matched and is left untouched
+	OutcomePassedThrough Outcome = iota
+	// OutcomeMapped the error was matched and mapped to another error
+	OutcomeMapped
+	// OutcomeIgnored the error was matched and should be ignored
+	OutcomeIgnored
+	// OutcomeDefaulted the error was not matched by any Mapper of a MultiErr,
+	// or was mapped to an error without status when the status is required, see WithStatusRequired,
+	// the default error is used when one is provided
+	OutcomeDefaulted
+	// OutcomeNil there was no error to map
+	OutcomeNil
+)
+
+// String returns a human readable representation of the outcome
+func (o Outcome) String() string {
+	switch o {
+	case OutcomePassedThrough:
+		return "passed through"
+	case OutcomeMapped:
+		return "mapped"
+	case OutcomeIgnored:
+		return "ignored"
+	case OutcomeDefaulted:
+		return "defaulted"
+	case OutcomeNil:
+		return "nil"
+	}
+	return "unknown"
+}
+
+// MapResult is an interface that defines the result of a MapErr operation
+type MapResult interface {
+	// Previous returns the error that has been mapped
+	Previous() error
+	// Last returns the error that the mapped error has been mapped to
+	Last() error
+	// Apply returns the error resulting from the mapping
+	Apply() error
+	// Outcome returns what the Mapper decided to do with the error
+	Outcome() Outcome
 }
 
-// Mapper takes an error and return a mapResult
+// Mapper takes an error and return a MapResult
+// A Mapper returns nil, or a MapResult with OutcomePassedThrough, when the error is not matched
 type Mapper interface {
-	mapErr(error) mapResult
+	MapErr(error) MapResult
+}
+
+// MapperFunc is an adapter to allow the use of ordinary functions as Mapper
+type MapperFunc func(error) MapResult
+
+// MapErr calls f(err)
+func (f MapperFunc) MapErr(err error) MapResult {
+	return f(err)
 }
 
 type mapperList []Mapper
 
-func (ml mapperList) mapErr(err error) mapResult {
+// mapErr returns the result of the first mapper matching err along with its position, -1 when none matched
+func (ml mapperList) mapErr(ctx context.Context, err error) (MapResult, int) {
 	for k := range ml {
-		if mapped := ml[k].mapErr(err); mapped != nil {
-			return mapped
+		if mapped := mapErrContext(ctx, ml[k], err); isMatched(mapped) {
+			return mapped, k
 		}
 	}
-	return nil
+	return nil, -1
+}
+
+// isMatched checks if a MapResult holds an error that was matched by a Mapper
+func isMatched(res MapResult) bool {
+	return res != nil && res.Outcome() != OutcomePassedThrough
 }
 
 // MultiErr an error to another error
 type MultiErr struct {
-	mappers mapperList
+	mappers    mapperList
+	observers  []Observer
+	precedence Precedence
+	combiner   Combiner
+	// statusRequired is set with WithStatusRequired
+	statusRequired bool
+	// canceled is the error used by the context aware methods when the context was canceled
+	canceled *canceledErr
 }
 
 // NewMultiErr return a new instance of MultiErr
@@ -42,27 +107,65 @@ func NewMultiErr(mapper ...Mapper) MultiErr {
 	}
 }
 
+// WithObservers returns a copy of the MultiErr which notifies the observers
+// of the outcome of every Mapped, MappedWithStatus and MappedWithGRPCStatus call
+func (m MultiErr) WithObservers(observers ...Observer) MultiErr {
+	m.observers = append(append([]Observer{}, m.observers...), observers...)
+	return m
+}
+
+// WithStatusRequired returns a copy of the MultiErr for which MappedWithStatus and MappedWithGRPCStatus
+// only return nil for the errors which are ignored: errors mapped to an error
+// without an http or gRPC status are given defaultErr, like the errors which are not mapped
+// e.g.: for servers which must answer every error with a status
+func (m MultiErr) WithStatusRequired() MultiErr {
+	m.statusRequired = true
+	return m
+}
+
 // Mapped appends the mapped error or a default one when is not found
 func (m MultiErr) Mapped(err, defaultErr error) error {
+	res, mapper := m.lastMapped(err)
+	mapped := m.mappedErr(err, defaultErr, res)
+	m.notify(newObservation(err, defaultErr, res, mapper, mapped, false))
+	return mapped
+}
+
+func (m MultiErr) mappedErr(err, defaultErr error, res MapResult) error {
 	if err == nil {
 		return nil
 	}
-	if res := m.mappers.mapErr(err); res != nil {
-		return res.apply()
+	if res != nil {
+		return res.Apply()
 	}
 	if defaultErr != nil {
-		return Append(err, defaultErr)
+		return m.append(err, defaultErr)
 	}
 	return err
 }
 
-// lastMapped return the lastErr mapped error
-func (m MultiErr) lastMapped(err error) mapResult {
-	res := m.mappers.mapErr(err)
+// lastMapped return the lastErr mapped error along with the position of the mapper which matched it
+func (m MultiErr) lastMapped(err error) (MapResult, int) {
+	return m.lastMappedContext(context.Background(), err)
+}
+
+// lastMappedContext return the lastErr mapped error along with the position of the mapper which matched it,
+// passing ctx to the mappers implementing ContextMapper
+func (m MultiErr) lastMappedContext(ctx context.Context, err error) (MapResult, int) {
+	if err == nil {
+		return nil, -1
+	}
+	var res MapResult
+	var mapper int
+	if m.precedence == PrecedenceMapperOrder {
+		res, mapper = m.mappers.mapErr(ctx, err)
+	} else {
+		res, mapper = m.precedence.mapErr(ctx, m.mappers, err)
+	}
 	if res == nil {
-		return nil
+		return nil, -1
 	}
-	return res
+	return m.withCombiner(res), mapper
 }
 
 // Default error with statuses
@@ -72,12 +175,21 @@ var (
 )
 
 // ErrorWithStatusProvider defines an error which also has an http status defined
+// Code returns the machine-readable code set with WithStatusCode, empty when not set
 type ErrorWithStatusProvider interface {
 	error
 	Status() int
+	Code() string
 	Unwrap() error
 }
 
+// ProblemProvider defines an error which holds the optional members of a problem details document (RFC 9457)
+// errors created with WithStatus implement it
+type ProblemProvider interface {
+	ProblemType() string
+	ProblemExtensions() map[string]interface{}
+}
+
 // MappedWithStatus return the lastErr mapped error with the associated http status
 // You can optionally provide a default error in case that will be returned if the error has not been mapped
 //
@@ -89,35 +201,48 @@ type ErrorWithStatusProvider interface {
 //
 // defaultErr.(error)                     will cast to a ErrorWithStatusProvider with http.StatusInternalServerError
 func (m MultiErr) MappedWithStatus(err, defaultErr error) ErrorWithStatusProvider {
+	res, mapper := m.lastMapped(err)
+	mapped, defaulted := m.mappedWithStatus(err, defaultErr, res)
+	m.notify(newObservation(err, defaultErr, res, mapper, mapped, defaulted))
+	return mapped
+}
+
+// mappedWithStatus also returns true when err was mapped to an error without status
+// and the default error was used instead, see WithStatusRequired
+func (m MultiErr) mappedWithStatus(err, defaultErr error, lastMappedResult MapResult) (ErrorWithStatusProvider, bool) {
 	if err == nil {
-		return nil
+		return nil, false
 	}
 
-	lastMappedResult := m.lastMapped(err)
-
 	// when the mapped error comes from the "ignore list" we can exit early
-	if _, ok := lastMappedResult.(ignoreStrategy); ok {
-		return nil
+	if lastMappedResult != nil && lastMappedResult.Outcome() == OutcomeIgnored {
+		return nil, false
 	}
 
 	// when have an error that could not be mapped, we use the defaultErr parameter instead
-	if lastMappedResult == nil && err != nil {
-		if defaultStatusErr := appendCauseToErrWithStatus(defaultErr, err); defaultStatusErr != nil {
-			return defaultStatusErr
-		}
-		if defaultErr != nil {
-			return newErrorWithStatus(defaultErr, err, http.StatusInternalServerError)
-		}
-	}
 	if lastMappedResult == nil {
-		return nil
+		return defaultWithStatus(err, defaultErr), false
 	}
 
-	lastMapped := lastMappedResult.last()
+	lastMapped := lastMappedResult.Last()
 	if statusErr := appendCauseToErrWithStatus(lastMapped, err); statusErr != nil {
-		return statusErr
+		return statusErr, false
+	}
+	if m.statusRequired {
+		return defaultWithStatus(err, defaultErr), true
 	}
 
+	return nil, false
+}
+
+// defaultWithStatus returns defaultErr with err as its cause, nil when defaultErr is nil
+func defaultWithStatus(err, defaultErr error) ErrorWithStatusProvider {
+	if defaultStatusErr := appendCauseToErrWithStatus(defaultErr, err); defaultStatusErr != nil {
+		return defaultStatusErr
+	}
+	if defaultErr != nil {
+		return newErrorWithStatus(defaultErr, err, http.StatusInternalServerError)
+	}
 	return nil
 }
 
@@ -131,6 +256,100 @@ func appendCauseToErrWithStatus(err, cause error) ErrorWithStatusProvider {
 	return errWithStatus
 }
 
+// Default error with gRPC codes
+var (
+	WithCodeInvalidArgument = WithCode(codes.InvalidArgument.String(), codes.InvalidArgument)
+	WithCodeInternal        = WithCode(codes.Internal.String(), codes.Internal)
+)
+
+// ErrorWithGRPCStatusProvider defines an error which also has a gRPC status defined
+// status.FromError and status.Code can be used on it
+type ErrorWithGRPCStatusProvider interface {
+	error
+	GRPCStatus() *status.Status
+	GRPCCode() codes.Code
+	Unwrap() error
+}
+
+// MappedWithGRPCStatus return the lastErr mapped error with the associated gRPC status
+// You can optionally provide a default error in case that will be returned if the error has not been mapped
+//
+// Errors mapped to maperr.WithCode keep their gRPC code, while errors mapped to maperr.WithStatus
+// have their http status converted to the closest gRPC code, so the same mappers can serve http and gRPC.
+//
+// defaultErr == nil                          returns the ErrorWithGRPCStatusProvider only if error is mapped
+//
+// defaultErr.(ErrorWithGRPCStatusProvider)   allow you to specify a gRPC code
+//                                            e.g.: maperr.WithCode("USER_ERROR", codes.InvalidArgument)
+//
+// defaultErr.(error)                         will cast to a ErrorWithGRPCStatusProvider with codes.Internal
+//
+// Errors which are not mapped but already carry a gRPC status, e.g.: created with status.Error,
+// are returned with their own status rather than defaultErr.
+func (m MultiErr) MappedWithGRPCStatus(err, defaultErr error) ErrorWithGRPCStatusProvider {
+	res, mapper := m.lastMapped(err)
+	mapped, defaulted := m.mappedWithGRPCStatus(err, defaultErr, res)
+	m.notify(newObservation(err, defaultErr, res, mapper, mapped, defaulted))
+	return mapped
+}
+
+// mappedWithGRPCStatus also returns true when err was mapped to an error without status
+// and the default error was used instead, see WithStatusRequired
+func (m MultiErr) mappedWithGRPCStatus(err, defaultErr error, lastMappedResult MapResult) (ErrorWithGRPCStatusProvider, bool) {
+	if err == nil {
+		return nil, false
+	}
+
+	// when the mapped error comes from the "ignore list" we can exit early
+	if lastMappedResult != nil && lastMappedResult.Outcome() == OutcomeIgnored {
+		return nil, false
+	}
+
+	// when have an error that could not be mapped, we keep its own gRPC status or use the defaultErr parameter instead
+	if lastMappedResult == nil {
+		if st, ok := status.FromError(err); ok {
+			return newErrorWithGRPCStatus(err, st), false
+		}
+		return defaultWithGRPCStatus(err, defaultErr), false
+	}
+
+	if codeErr := appendCauseToErrWithCode(lastMappedResult.Last(), err); codeErr != nil {
+		return codeErr, false
+	}
+	if m.statusRequired {
+		return defaultWithGRPCStatus(err, defaultErr), true
+	}
+	return nil, false
+}
+
+// defaultWithGRPCStatus returns defaultErr with err as its cause, nil when defaultErr is nil
+func defaultWithGRPCStatus(err, defaultErr error) ErrorWithGRPCStatusProvider {
+	if defaultCodeErr := appendCauseToErrWithCode(defaultErr, err); defaultCodeErr != nil {
+		return defaultCodeErr
+	}
+	if defaultErr != nil {
+		return newErrorWithCode(defaultErr, err, codes.Internal)
+	}
+	return nil
+}
+
+func appendCauseToErrWithCode(err, cause error) ErrorWithGRPCStatusProvider {
+	var errWithCode errorWithCode
+	if errors.As(err, &errWithCode) {
+		errWithCode.cause = cause
+		return errWithCode
+	}
+
+	var errWithStatus errorWithStatus
+	if errors.As(err, &errWithStatus) {
+		errWithCode = newErrorWithCode(errWithStatus.err, cause, codeFromHTTPStatus(errWithStatus.status))
+		errWithCode.reason = errWithStatus.code
+		return errWithCode
+	}
+
+	return nil
+}
+
 // LastMappedWithStatus return the lastErr mapped error with the associated http status
 // Deprecated: consider using MappedWithStatus() instead, as encourages to specify a default error
 func (m MultiErr) LastMappedWithStatus(err error) ErrorWithStatusProvider {
@@ -138,11 +357,12 @@ func (m MultiErr) LastMappedWithStatus(err error) ErrorWithStatusProvider {
 }
 
 // LastAppended return the lastErr error appended as multierr
+// or with any other Combiner
 func LastAppended(err error) error {
-	if errList := multierr.Errors(err); len(errList) > 0 {
-		return errList[len(errList)-1]
+	if errList := combinedErrors(err); len(errList) > 0 {
+		return LastAppended(errList[len(errList)-1])
 	}
-	return nil
+	return err
 }
 
 // HasEqual find the first equal error on a chain of errors
@@ -150,12 +370,7 @@ func LastAppended(err error) error {
 func HasEqual(chain, err error) Error {
 	mapError := castError(err)
 
-	multiErrList := multierr.Errors(chain)
-	if len(multiErrList) == 0 {
-		return nil
-	}
-
-	for _, wrapped := range multiErrList {
+	for _, wrapped := range flatten(chain) {
 		wrappedMapError := castError(wrapped)
 		if wrappedMapError.Equal(mapError) {
 			return wrappedMapError
@@ -166,26 +381,30 @@ func HasEqual(chain, err error) Error {
 
 // HasError checks if an error has been wrapped
 func HasError(err error, errText string) bool {
-	multiErrList := multierr.Errors(err)
-	if len(multiErrList) == 0 {
-		return false
-	}
-	found := false
-	for _, wrapped := range multiErrList {
+	for _, wrapped := range flatten(err) {
 		if wrapped.Error() == errText {
-			found = true
-			break
+			return true
 		}
 	}
-	return found
+	return false
 }
 
-// Append appends the given errors together. Either value may be nil.
+// Append appends the given errors together using go.uber.org/multierr. Either value may be nil.
 func Append(left, right error) error {
-	return multierr.Append(left, right)
+	return MultiErrCombiner.Append(left, right)
 }
 
-// Combine combines the passed errors into a single error.
+// Combine combines the passed errors into a single error using go.uber.org/multierr.
 func Combine(errList ...error) error {
-	return multierr.Combine(errList...)
+	return MultiErrCombiner.Combine(errList...)
+}
+
+// AppendJoin appends the given errors together using errors.Join. Either value may be nil.
+func AppendJoin(left, right error) error {
+	return JoinCombiner.Append(left, right)
+}
+
+// CombineJoin combines the passed errors into a single error using errors.Join.
+func CombineJoin(errList ...error) error {
+	return JoinCombiner.Combine(errList...)
 }
@@ -2,11 +2,14 @@ package maperr_test
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/multierr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/iZettle/maperr/v4"
 )
@@ -291,6 +294,164 @@ func TestMultiErr_MappedWithStatus(t *testing.T) {
 	}
 }
 
+func TestMultiErr_MappedWithGRPCStatus(t *testing.T) {
+	first := errors.New("first")
+	second := errors.New("second")
+	third := errors.New("third")
+	ignored := errors.New("ignored")
+
+	errMapper := maperr.NewMultiErr(
+		maperr.NewHashableMapper().
+			Append(second, maperr.WithCode("user not found", codes.NotFound)).
+			Append(third, maperr.WithStatus("conflict", http.StatusConflict)),
+		maperr.NewListMapper().
+			Append(first, errors.New("no code")),
+		maperr.NewIgnoreListMapper().
+			Append(ignored),
+	)
+
+	type expected struct {
+		code  codes.Code
+		err   string
+		cause string
+	}
+	tests := []struct {
+		name         string
+		givenError   error
+		givenDefault error
+		expected     expected
+	}{
+		{
+			name:         "there was no error",
+			givenError:   nil,
+			givenDefault: maperr.WithCodeInternal,
+		},
+		{
+			name:       "error was not found and nothing was provided",
+			givenError: errors.New("not found"),
+		},
+		{
+			name:         "error was not found and a simple error was provided",
+			givenError:   errors.New("not found"),
+			givenDefault: errors.New("default error without a code"),
+			expected: expected{
+				code:  codes.Internal,
+				err:   "default error without a code",
+				cause: "not found",
+			},
+		},
+		{
+			name:         "error was not found and an error with code was provided",
+			givenError:   errors.New("not found"),
+			givenDefault: maperr.WithCodeInvalidArgument,
+			expected: expected{
+				code:  codes.InvalidArgument,
+				err:   "InvalidArgument",
+				cause: "not found",
+			},
+		},
+		{
+			name:         "error was not found and an error with http status was provided",
+			givenError:   errors.New("not found"),
+			givenDefault: maperr.WithStatusBadRequest,
+			expected: expected{
+				code:  codes.InvalidArgument,
+				err:   "Bad Request",
+				cause: "not found",
+			},
+		},
+		{
+			name:         "error was mapped to a gRPC code",
+			givenError:   maperr.Append(errors.New("zero"), fmt.Errorf("select: %w", second)),
+			givenDefault: maperr.WithCodeInternal,
+			expected: expected{
+				code:  codes.NotFound,
+				err:   "user not found",
+				cause: "zero; select: second",
+			},
+		},
+		{
+			name:         "error was mapped to an http status",
+			givenError:   third,
+			givenDefault: maperr.WithCodeInternal,
+			expected: expected{
+				code:  codes.AlreadyExists,
+				err:   "conflict",
+				cause: "third",
+			},
+		},
+		{
+			name:         "error was mapped without a code",
+			givenError:   first,
+			givenDefault: maperr.WithCodeInternal,
+		},
+		{
+			name:         "error was ignored",
+			givenError:   maperr.Append(errors.New("zero"), ignored),
+			givenDefault: maperr.WithCodeInternal,
+		},
+		{
+			name:         "error was not found and already has a gRPC status",
+			givenError:   fmt.Errorf("call: %w", status.Error(codes.InvalidArgument, "bad id")),
+			givenDefault: maperr.WithCodeInternal,
+			expected: expected{
+				code:  codes.InvalidArgument,
+				err:   "call: rpc error: code = InvalidArgument desc = bad id",
+				cause: "call: rpc error: code = InvalidArgument desc = bad id",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actualErr := errMapper.MappedWithGRPCStatus(test.givenError, test.givenDefault)
+			if test.expected.err == "" {
+				assert.NoError(t, actualErr)
+				return
+			}
+
+			assert.EqualError(t, actualErr, test.expected.err)
+			assert.EqualError(t, actualErr.Unwrap(), test.expected.cause)
+			assert.Equal(t, test.expected.code, actualErr.GRPCCode())
+
+			st, ok := status.FromError(actualErr)
+			assert.True(t, ok)
+			assert.Equal(t, test.expected.code, st.Code())
+			assert.Equal(t, test.expected.err, st.Message())
+		})
+	}
+}
+
+func TestMultiErr_WithStatusRequired(t *testing.T) {
+	errNoCode := errors.New("no code")
+	errIgnored := errors.New("ignored")
+
+	errMapper := maperr.NewMultiErr(
+		maperr.NewHashableMapper().
+			Append(errNoCode, errors.New("mapped without code")),
+		maperr.NewIgnoreListMapper().
+			Append(errIgnored),
+	).WithStatusRequired()
+
+	mapped := errMapper.MappedWithGRPCStatus(errNoCode, maperr.WithCodeInvalidArgument)
+	if assert.NotNil(t, mapped) {
+		assert.Equal(t, codes.InvalidArgument, mapped.GRPCCode())
+		assert.Equal(t, errNoCode, mapped.Unwrap())
+	}
+
+	assert.Nil(t, errMapper.MappedWithGRPCStatus(errIgnored, maperr.WithCodeInvalidArgument))
+	assert.Nil(t, errMapper.MappedWithGRPCStatus(errNoCode, nil))
+
+	withStatus := errMapper.MappedWithStatus(errNoCode, maperr.WithStatusBadRequest)
+	if assert.NotNil(t, withStatus) {
+		assert.Equal(t, http.StatusBadRequest, withStatus.Status())
+		assert.Equal(t, errNoCode, withStatus.Unwrap())
+	}
+
+	assert.Nil(t, errMapper.MappedWithStatus(errIgnored, maperr.WithStatusBadRequest))
+	assert.Nil(t, maperr.NewMultiErr(maperr.NewHashableMapper().Append(errNoCode, errors.New("mapped without code"))).
+		MappedWithStatus(errNoCode, maperr.WithStatusBadRequest))
+}
+
 func TestHasError(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -422,3 +583,157 @@ func Test_HasEqual(t *testing.T) {
 		})
 	}
 }
+
+// timeoutMapper is a custom Mapper which maps any error exposing a Timeout() method
+type timeoutMapper struct {
+	match error
+}
+
+func (tm timeoutMapper) MapErr(err error) maperr.MapResult {
+	var timeout interface{ Timeout() bool }
+	if errors.As(err, &timeout) && timeout.Timeout() {
+		return maperr.NewAppendStrategy(err, tm.match)
+	}
+	return nil
+}
+
+type timeoutErr struct{}
+
+func (timeoutErr) Error() string { return "i/o timeout" }
+func (timeoutErr) Timeout() bool { return true }
+
+func TestMultiErr_CustomMapper(t *testing.T) {
+	errTimeout := maperr.WithStatus("timeout", http.StatusGatewayTimeout)
+
+	errMapper := maperr.NewMultiErr(
+		timeoutMapper{match: errTimeout},
+		maperr.NewIgnoreListMapper().
+			Append(timeoutErr{}),
+	)
+
+	mappedErr := errMapper.MappedWithStatus(timeoutErr{}, maperr.WithStatusInternalServerError)
+	if assert.Error(t, mappedErr) {
+		assert.EqualError(t, mappedErr, "timeout")
+		assert.Equal(t, http.StatusGatewayTimeout, mappedErr.Status())
+		assert.EqualError(t, mappedErr.Unwrap(), "i/o timeout")
+	}
+
+	assert.EqualError(t, errMapper.Mapped(timeoutErr{}, nil), "i/o timeout; timeout")
+}
+
+func TestMultiErr_CustomMapper_PassedThrough(t *testing.T) {
+	errMapped := errors.New("mapped")
+	errMapper := maperr.NewMultiErr(
+		passThroughMapper{},
+		maperr.NewHashableMapper().
+			Append(timeoutErr{}, errMapped),
+	)
+
+	assert.EqualError(t, errMapper.Mapped(timeoutErr{}, nil), "i/o timeout; mapped")
+}
+
+type passThroughMapper struct{}
+
+func (passThroughMapper) MapErr(err error) maperr.MapResult {
+	return passedThroughResult{err: err}
+}
+
+type passedThroughResult struct {
+	err error
+}
+
+func (pr passedThroughResult) Previous() error         { return pr.err }
+func (pr passedThroughResult) Last() error             { return nil }
+func (pr passedThroughResult) Apply() error            { return pr.err }
+func (pr passedThroughResult) Outcome() maperr.Outcome { return maperr.OutcomePassedThrough }
+
+func TestMultiErr_Mapped_WrapChains(t *testing.T) {
+	errNoRows := errors.New("no rows")
+	errIgnored := errors.New("ignored")
+	errUserNotFound := errors.New("user not found")
+	errConflict := errors.New("conflict")
+
+	errMapper := maperr.NewMultiErr(
+		maperr.NewHashableMapper().
+			Append(errNoRows, errUserNotFound),
+		maperr.NewListMapper().
+			Appendf("duplicate key %s", errConflict),
+		maperr.NewIgnoreListMapper().
+			Append(errIgnored),
+	)
+
+	tests := []struct {
+		name        string
+		givenError  error
+		expectedErr string
+	}{
+		{
+			name:        "hashable error wrapped with fmt.Errorf",
+			givenError:  fmt.Errorf("update user: %w", errNoRows),
+			expectedErr: "update user: no rows; user not found",
+		},
+		{
+			name:        "formatted error wrapped within a multierr",
+			givenError:  maperr.Combine(errors.New("first"), fmt.Errorf("insert: %w", maperr.Errorf("duplicate key %s", "email"))),
+			expectedErr: "first; insert: duplicate key email; conflict",
+		},
+		{
+			name:        "hashable error joined with errors.Join",
+			givenError:  errors.Join(errors.New("first"), errNoRows),
+			expectedErr: "first\nno rows; user not found",
+		},
+		{
+			name:        "ignored error wrapped twice",
+			givenError:  fmt.Errorf("handler: %w", fmt.Errorf("storage: %w", errIgnored)),
+			expectedErr: "",
+		},
+		{
+			name:        "first mapper wins over a more recently appended error",
+			givenError:  maperr.Combine(fmt.Errorf("read: %w", errNoRows), maperr.Errorf("duplicate key %s", "email")),
+			expectedErr: "read: no rows; duplicate key email; user not found",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actualErr := errMapper.Mapped(test.givenError, nil)
+			if test.expectedErr == "" {
+				assert.NoError(t, actualErr)
+			} else {
+				assert.EqualError(t, actualErr, test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestListMapper_Mapped_WrapChainsPrecedence(t *testing.T) {
+	errNoRows := errors.New("no rows")
+	errDuplicate := maperr.Errorf("duplicate key %s", "email")
+
+	errMapper := maperr.NewMultiErr(
+		maperr.NewListMapper().
+			Append(errNoRows, errors.New("user not found")).
+			Append(errDuplicate, errors.New("conflict")),
+	)
+
+	tests := []struct {
+		name        string
+		givenError  error
+		expectedErr string
+	}{
+		{
+			name:        "most recently appended error wins",
+			givenError:  maperr.Combine(errDuplicate, fmt.Errorf("read: %w", errNoRows)),
+			expectedErr: "duplicate key email; read: no rows; user not found",
+		},
+		{
+			name:        "outer error wins over the error it wraps",
+			givenError:  fmt.Errorf("%w", errors.Join(errNoRows, fmt.Errorf("insert: %w", errDuplicate))),
+			expectedErr: "no rows\ninsert: duplicate key email; conflict",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.EqualError(t, errMapper.Mapped(test.givenError, nil), test.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,15 @@
+// Command maperrcheck reports the sentinel errors which are never mapped by a maperr mapper
+//
+//	go vet -vettool=$(which maperrcheck) ./...
+//	maperrcheck -packages '(^|/)(domain|storage)(/|$)' ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/iZettle/maperr/v4/maperrcheck"
+)
+
+func main() {
+	singlechecker.Main(maperrcheck.Analyzer)
+}
@@ -0,0 +1,9 @@
+module github.com/iZettle/maperr/v4/maperrcheck
+
+go 1.24.0
+
+require (
+	golang.org/x/mod v0.30.0 // indirect
+	golang.org/x/sync v0.18.0 // indirect
+	golang.org/x/tools v0.39.0
+)
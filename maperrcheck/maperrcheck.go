@@ -0,0 +1,343 @@
+// Package maperrcheck defines an analyzer reporting the sentinel errors which are never mapped
+//
+// Sentinel errors are the exported package level errors named Err... declared in the packages
+// matching the -packages flag. They are considered mapped when used as the key of
+// HashableMapper.Append, ListMapper.Append, ListMapper.Appendf, IgnoreListMapper.Append,
+// RegistryMapper.Register, RegistryMapper.Registerf or RegistryMapper.Ignore, or when their format is.
+// Errors bound to a name with config.Registry.Register or config.Registry.RegisterFormat are considered
+// mapped as well, without checking the configuration uses the name, and so are errors compared with
+// errors.Is within a function literal given to FuncMapper.Append or FuncMapper.AppendFunc,
+// functions given by name are not looked into. As the mappings are usually made in another package than
+// the declarations, the unmapped errors are reported once every package of a program is analyzed,
+// on its main package.
+//
+// It also reports maperr.NewError called with fmt.Sprintf, as the dynamic text can not be matched.
+package maperrcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const (
+	maperrPath = "github.com/iZettle/maperr/v4"
+	configPath = maperrPath + "/config"
+)
+
+// Analyzer reports sentinel errors never mapped, and errors created from dynamic text
+var Analyzer = &analysis.Analyzer{
+	Name:      "maperrcheck",
+	Doc:       "report sentinel errors which are never mapped by a maperr mapper",
+	Run:       run,
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	FactTypes: []analysis.Fact{new(mappingsFact)},
+}
+
+var packages string
+
+func init() {
+	Analyzer.Flags.StringVar(&packages, "packages", `(^|/)(domain|storage)(/|$)`,
+		"regular expression matching the import path of the packages declaring sentinel errors")
+}
+
+// mappingKeys are the methods adding a rule for an error, by type,
+// with the position of the argument holding the error or its format
+var mappingKeys = map[string]map[string]int{
+	"HashableMapper":   {"Append": 0},
+	"ListMapper":       {"Append": 0, "Appendf": 0},
+	"IgnoreListMapper": {"Append": 0, "Appendf": 0},
+	"RegistryMapper":   {"Register": 0, "Registerf": 0, "Ignore": 0},
+	"config.Registry":  {"Register": 1, "RegisterFormat": 1},
+}
+
+// predicateMethods are the methods of FuncMapper taking a function matching errors
+var predicateMethods = map[string]bool{"Append": true, "AppendFunc": true}
+
+// sentinel is an error declared in a package matching -packages
+type sentinel struct {
+	ID       string
+	Format   string
+	Position string
+}
+
+// mappingsFact holds the sentinel errors and the mapping keys of a package and of its dependencies
+type mappingsFact struct {
+	Sentinels map[string]sentinel
+	Keys      map[string]bool
+	Formats   map[string]bool
+}
+
+// AFact marks mappingsFact as an analysis.Fact
+func (*mappingsFact) AFact() {}
+
+func (f *mappingsFact) String() string {
+	return fmt.Sprintf("%d sentinels, %d keys, %d formats", len(f.Sentinels), len(f.Keys), len(f.Formats))
+}
+
+func (f *mappingsFact) merge(other mappingsFact) {
+	for id, s := range other.Sentinels {
+		f.Sentinels[id] = s
+	}
+	for key := range other.Keys {
+		f.Keys[key] = true
+	}
+	for format := range other.Formats {
+		f.Formats[format] = true
+	}
+}
+
+func (f *mappingsFact) isMapped(s sentinel) bool {
+	return f.Keys[s.ID] || (s.Format != "" && f.Formats[s.Format])
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	packagesRegexp, err := regexp.Compile(packages)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -packages: %w", err)
+	}
+
+	fact := mappingsFact{
+		Sentinels: map[string]sentinel{},
+		Keys:      map[string]bool{},
+		Formats:   map[string]bool{},
+	}
+	for _, imp := range pass.Pkg.Imports() {
+		var importedFact mappingsFact
+		if pass.ImportPackageFact(imp, &importedFact) {
+			fact.merge(importedFact)
+		}
+	}
+
+	if packagesRegexp.MatchString(pass.Pkg.Path()) {
+		for _, s := range sentinels(pass) {
+			fact.Sentinels[s.ID] = s
+		}
+	}
+
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		if key, ok := mappingKey(pass, call); ok {
+			addKey(pass, &fact, key)
+		}
+		if isPredicateCall(pass, call) && len(call.Args) > 0 {
+			addComparedKeys(pass, &fact, call.Args[0])
+		}
+		if isMaperrFunc(pass, call, "NewError") && len(call.Args) > 0 && isSprintf(pass, call.Args[0]) {
+			pass.Reportf(call.Pos(), "maperr.NewError called with fmt.Sprintf: the text can not be matched, use maperr.Errorf instead")
+		}
+	})
+
+	pass.ExportPackageFact(&fact)
+
+	if pass.Pkg.Name() == "main" && len(pass.Files) > 0 {
+		reportUnmapped(pass, fact)
+	}
+	return nil, nil
+}
+
+// sentinels returns the exported package level errors named Err... of the package
+func sentinels(pass *analysis.Pass) []sentinel {
+	errorType := types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+	formats := errorfFormats(pass)
+
+	var found []sentinel
+	scope := pass.Pkg.Scope()
+	for _, name := range scope.Names() {
+		v, ok := scope.Lookup(name).(*types.Var)
+		if !ok || !v.Exported() || !strings.HasPrefix(name, "Err") || !types.Implements(v.Type(), errorType) {
+			continue
+		}
+		found = append(found, sentinel{
+			ID:       objectID(v),
+			Format:   formats[v],
+			Position: pass.Fset.Position(v.Pos()).String(),
+		})
+	}
+	return found
+}
+
+// errorfFormats returns the format of the package level variables declared with maperr.Errorf
+func errorfFormats(pass *analysis.Pass) map[types.Object]string {
+	formats := map[types.Object]string{}
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				valueSpec := spec.(*ast.ValueSpec)
+				for i, ident := range valueSpec.Names {
+					if i >= len(valueSpec.Values) {
+						break
+					}
+					if format, ok := errorfFormat(pass, valueSpec.Values[i]); ok {
+						formats[pass.TypesInfo.Defs[ident]] = format
+					}
+				}
+			}
+		}
+	}
+	return formats
+}
+
+// errorfFormat returns the format of a maperr.Errorf call with a constant format
+func errorfFormat(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || !isMaperrFunc(pass, call, "Errorf") || len(call.Args) == 0 {
+		return "", false
+	}
+	return constantString(pass, call.Args[0])
+}
+
+// mappingKey returns the argument holding the error or the format of a call adding a rule,
+// e.g.: the first argument of ListMapper.Append
+func mappingKey(pass *analysis.Pass, call *ast.CallExpr) (ast.Expr, bool) {
+	recv, method, ok := methodOf(pass, call)
+	if !ok {
+		return nil, false
+	}
+	k, ok := mappingKeys[recv][method]
+	if !ok || k >= len(call.Args) {
+		return nil, false
+	}
+	return call.Args[k], true
+}
+
+// isPredicateCall checks if the call adds a function matching errors to a FuncMapper
+func isPredicateCall(pass *analysis.Pass, call *ast.CallExpr) bool {
+	recv, method, ok := methodOf(pass, call)
+	return ok && recv == "FuncMapper" && predicateMethods[method]
+}
+
+// methodOf returns the receiver type and the name of a method of the maperr or config packages called by call,
+// the receiver type of the config package being prefixed with "config."
+func methodOf(pass *analysis.Pass, call *ast.CallExpr) (string, string, bool) {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return "", "", false
+	}
+	selection := pass.TypesInfo.Selections[sel]
+	if selection == nil || selection.Kind() != types.MethodVal {
+		return "", "", false
+	}
+	recv := selection.Recv()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	named, ok := recv.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return "", "", false
+	}
+	switch named.Obj().Pkg().Path() {
+	case maperrPath:
+		return named.Obj().Name(), sel.Sel.Name, true
+	case configPath:
+		return "config." + named.Obj().Name(), sel.Sel.Name, true
+	}
+	return "", "", false
+}
+
+// addComparedKeys adds the errors compared with errors.Is within a function literal
+func addComparedKeys(pass *analysis.Pass, fact *mappingsFact, fn ast.Expr) {
+	lit, ok := ast.Unparen(fn).(*ast.FuncLit)
+	if !ok {
+		return
+	}
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok && isFunc(pass, call, "errors", "Is") && len(call.Args) == 2 {
+			addKey(pass, fact, call.Args[1])
+		}
+		return true
+	})
+}
+
+// addKey adds the key of a mapping to the fact, either a package level variable or a format
+func addKey(pass *analysis.Pass, fact *mappingsFact, key ast.Expr) {
+	if format, ok := constantString(pass, key); ok {
+		fact.Formats[format] = true
+		return
+	}
+	if format, ok := errorfFormat(pass, key); ok {
+		fact.Formats[format] = true
+		return
+	}
+
+	var ident *ast.Ident
+	switch expr := ast.Unparen(key).(type) {
+	case *ast.Ident:
+		ident = expr
+	case *ast.SelectorExpr:
+		ident = expr.Sel
+	default:
+		return
+	}
+	v, ok := pass.TypesInfo.Uses[ident].(*types.Var)
+	if ok && v.Pkg() != nil && v.Parent() == v.Pkg().Scope() {
+		fact.Keys[objectID(v)] = true
+	}
+}
+
+// isMaperrFunc checks if the call is a call to the function of the maperr package with the given name
+func isMaperrFunc(pass *analysis.Pass, call *ast.CallExpr, name string) bool {
+	return isFunc(pass, call, maperrPath, name)
+}
+
+// isSprintf checks if the expression is a call to fmt.Sprintf
+func isSprintf(pass *analysis.Pass, expr ast.Expr) bool {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	return ok && isFunc(pass, call, "fmt", "Sprintf")
+}
+
+func isFunc(pass *analysis.Pass, call *ast.CallExpr, pkgPath, name string) bool {
+	var ident *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return false
+	}
+	fn, ok := pass.TypesInfo.Uses[ident].(*types.Func)
+	return ok && fn.Pkg() != nil && fn.Pkg().Path() == pkgPath && fn.Name() == name
+}
+
+func constantString(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+func objectID(obj types.Object) string {
+	return obj.Pkg().Path() + "." + obj.Name()
+}
+
+// reportUnmapped reports the sentinel errors of the program which are never mapped, on its package clause
+func reportUnmapped(pass *analysis.Pass, fact mappingsFact) {
+	var unmapped []sentinel
+	for _, s := range fact.Sentinels {
+		if !fact.isMapped(s) {
+			unmapped = append(unmapped, s)
+		}
+	}
+	sort.Slice(unmapped, func(i, j int) bool {
+		return unmapped[i].ID < unmapped[j].ID
+	})
+	for _, s := range unmapped {
+		pass.Reportf(pass.Files[0].Package, "%s declared at %s is never mapped", s.ID, s.Position)
+	}
+}
@@ -0,0 +1,16 @@
+package maperrcheck_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/iZettle/maperr/v4/maperrcheck"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), maperrcheck.Analyzer,
+		"example.com/app/domain",
+		"example.com/app/cmd/api",
+	)
+}
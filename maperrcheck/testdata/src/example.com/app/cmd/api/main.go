@@ -0,0 +1,7 @@
+package main // want package:"13 sentinels, 6 keys, 4 formats" `example.com/app/domain.ErrUserSuspended declared at .* is never mapped` `example.com/app/storage.ErrDuplicate declared at .* is never mapped` `example.com/app/storage.ErrBusy declared at .* is never mapped`
+
+import "example.com/app/handler"
+
+func main() {
+	handler.Mapper()
+}
@@ -0,0 +1,15 @@
+package domain // want package:"2 sentinels, 0 keys, 0 formats"
+
+import (
+	"fmt"
+
+	"github.com/iZettle/maperr/v4"
+)
+
+var ErrUserLocked = maperr.Errorf("user %s is locked")
+
+var ErrUserSuspended = maperr.NewError("user is suspended")
+
+func Lock(name string) error {
+	return maperr.NewError(fmt.Sprintf("user %s is locked", name)) // want `maperr.NewError called with fmt.Sprintf`
+}
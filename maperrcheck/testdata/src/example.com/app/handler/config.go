@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"errors"
+
+	"example.com/app/storage"
+
+	"github.com/iZettle/maperr/v4"
+	"github.com/iZettle/maperr/v4/config"
+)
+
+var registry = config.NewRegistry().
+	Register("storage.limited", storage.ErrLimited).
+	RegisterFormat("storage.throttled", "throttled for %s")
+
+var funcMapper = maperr.NewFuncMapper().
+	Append(func(err error) bool { return errors.Is(err, storage.ErrOffline) }, maperr.WithStatus("offline", 503)).
+	Append(isBusy, maperr.WithStatus("busy", 503))
+
+func isBusy(err error) bool {
+	return errors.Is(err, storage.ErrBusy)
+}
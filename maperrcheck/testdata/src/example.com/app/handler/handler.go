@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"example.com/app/domain"
+	"example.com/app/storage"
+
+	"github.com/iZettle/maperr/v4"
+)
+
+var ErrNotMatched = maperr.NewError("handlers are not in a checked package")
+
+var errMapper = maperr.NewMultiErr(
+	maperr.NewHashableMapper().
+		Append(storage.ErrNotFound, maperr.WithStatus("not found", 404)),
+	maperr.NewListMapper().
+		Appendf("conflict on %s", maperr.WithStatus("conflict", 409)).
+		Append(maperr.Errorf("user %s is locked", "bob"), maperr.WithStatus("locked", 423)),
+	maperr.NewIgnoreListMapper().
+		Append(storage.ErrCanceled),
+	maperr.NewRegistryMapper().
+		Register(storage.ErrExpired, maperr.WithStatus("expired", 410)).
+		Registerf("row %s is stale", maperr.WithStatus("stale", 409)).
+		Ignore(storage.ErrRetried),
+)
+
+func Mapper() maperr.MultiErr {
+	_ = domain.Lock
+	return errMapper
+}
@@ -0,0 +1,24 @@
+package storage
+
+import (
+	"errors"
+
+	"github.com/iZettle/maperr/v4"
+)
+
+var (
+	ErrNotFound  = errors.New("not found")
+	ErrDuplicate = errors.New("duplicate")
+	ErrConflict  = maperr.Errorf("conflict on %s")
+	ErrCanceled  = errors.New("canceled")
+	ErrExpired   = errors.New("expired")
+	ErrStale     = maperr.Errorf("row %s is stale")
+	ErrRetried   = errors.New("retried")
+	ErrLimited   = errors.New("limited")
+	ErrThrottled = maperr.Errorf("throttled for %s")
+	ErrOffline   = errors.New("offline")
+	ErrBusy      = errors.New("busy")
+
+	errInternal = errors.New("internal")
+	Timeout     = errors.New("timeout")
+)
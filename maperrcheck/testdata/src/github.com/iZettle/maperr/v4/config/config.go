@@ -0,0 +1,13 @@
+package config
+
+import "github.com/iZettle/maperr/v4"
+
+type Registry struct{}
+
+func NewRegistry() *Registry { return &Registry{} }
+
+func (r *Registry) Register(name string, err error) *Registry { return r }
+
+func (r *Registry) RegisterFormat(name, format string) *Registry { return r }
+
+func (r *Registry) LoadYAML(data []byte) (maperr.MultiErr, error) { return maperr.MultiErr{}, nil }
@@ -0,0 +1,58 @@
+// Package maperr is a stub of the maperr package, limited to what the analyzer looks for
+package maperr
+
+import "errors"
+
+type Error interface {
+	error
+}
+
+func Errorf(format string, args ...interface{}) Error { return nil }
+
+func NewError(errText string) Error { return nil }
+
+func WithStatus(err string, status int) error { return errors.New(err) }
+
+type HashableMapper map[error]error
+
+func NewHashableMapper() HashableMapper { return HashableMapper{} }
+
+func (hm HashableMapper) Append(err, match error) HashableMapper { return hm }
+
+type ListMapper struct{}
+
+func NewListMapper() ListMapper { return ListMapper{} }
+
+func (lm ListMapper) Append(err, match error) ListMapper { return lm }
+
+func (lm ListMapper) Appendf(format string, match error) ListMapper { return lm }
+
+type IgnoreListMapper struct{}
+
+func NewIgnoreListMapper() IgnoreListMapper { return IgnoreListMapper{} }
+
+func (lm IgnoreListMapper) Append(err error) IgnoreListMapper { return lm }
+
+type RegistryMapper struct{}
+
+func NewRegistryMapper() *RegistryMapper { return &RegistryMapper{} }
+
+func (r *RegistryMapper) Register(err, match error) *RegistryMapper { return r }
+
+func (r *RegistryMapper) Registerf(format string, match error) *RegistryMapper { return r }
+
+func (r *RegistryMapper) Ignore(err error) *RegistryMapper { return r }
+
+type FuncMapper struct{}
+
+func NewFuncMapper() FuncMapper { return FuncMapper{} }
+
+func (fm FuncMapper) Append(predicate func(error) bool, match error) FuncMapper { return fm }
+
+func (fm FuncMapper) AppendFunc(transform func(error) (error, bool)) FuncMapper { return fm }
+
+type MultiErr struct{}
+
+type Mapper interface{}
+
+func NewMultiErr(mappers ...Mapper) MultiErr { return MultiErr{} }
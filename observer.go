@@ -0,0 +1,89 @@
+package maperr
+
+import (
+	"google.golang.org/grpc/codes"
+)
+
+// Observation describes the outcome of a Mapped, MappedWithStatus or MappedWithGRPCStatus call
+type Observation struct {
+	// Err is the error which was given to be mapped
+	Err error
+	// Mapped is the error which was returned
+	Mapped error
+	// Rule is the rule which matched Err, nil when not matched or when the Mapper does not implement RuleProvider
+	Rule *Rule
+	// Mapper is the position of the mapper which matched Err within the MultiErr, -1 when not matched
+	Mapper int
+	// Outcome is what was decided: mapped, ignored, defaulted, passed through, or nil when there was no error
+	Outcome Outcome
+	// Status is the http status of the returned error, 0 when it has none
+	Status int
+	// GRPCCode is the gRPC code of the error returned by MappedWithGRPCStatus, codes.OK otherwise
+	GRPCCode codes.Code
+}
+
+// Observer is notified of the outcome of the mapping operations of a MultiErr
+// e.g.: to count how often each rule matches, or how often the default error is used
+type Observer interface {
+	Observe(Observation)
+}
+
+// ObserverFunc is an adapter to allow the use of ordinary functions as Observer
+type ObserverFunc func(Observation)
+
+// Observe calls f(o)
+func (f ObserverFunc) Observe(o Observation) {
+	f(o)
+}
+
+// notify notifies every observer of the MultiErr
+func (m MultiErr) notify(o Observation) {
+	for k := range m.observers {
+		m.observers[k].Observe(o)
+	}
+}
+
+// keepsGRPCStatus checks if mapped is an error which was not mapped and kept its own gRPC status
+func keepsGRPCStatus(mapped error) bool {
+	_, ok := mapped.(errorWithGRPCStatus)
+	return ok
+}
+
+// newObservation describes the outcome of mapping err,
+// defaulted is true when err was mapped to an error without status and the default error was used instead
+func newObservation(err, defaultErr error, res MapResult, mapper int, mapped error, defaulted bool) Observation {
+	o := Observation{
+		Err:    err,
+		Mapped: mapped,
+		Mapper: mapper,
+	}
+
+	switch {
+	case err == nil:
+		o.Outcome = OutcomeNil
+	case defaulted:
+		o.Outcome = OutcomeDefaulted
+	case res != nil:
+		o.Outcome = res.Outcome()
+	case keepsGRPCStatus(mapped):
+		o.Outcome = OutcomePassedThrough
+	case defaultErr != nil:
+		o.Outcome = OutcomeDefaulted
+	default:
+		o.Outcome = OutcomePassedThrough
+	}
+
+	if provider, ok := res.(RuleProvider); ok {
+		if rule, ok := provider.Rule(); ok {
+			o.Rule = &rule
+		}
+	}
+	if errWithStatus, ok := mapped.(ErrorWithStatusProvider); ok {
+		o.Status = errWithStatus.Status()
+	}
+	if errWithCode, ok := mapped.(ErrorWithGRPCStatusProvider); ok {
+		o.GRPCCode = errWithCode.GRPCCode()
+	}
+
+	return o
+}
@@ -0,0 +1,154 @@
+package maperr_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+
+	"github.com/iZettle/maperr/v4"
+)
+
+func TestMultiErr_WithObservers(t *testing.T) {
+	errNoRows := errors.New("no rows")
+	errIgnored := errors.New("ignored")
+
+	var observations []maperr.Observation
+	errMapper := maperr.NewMultiErr(
+		maperr.NewListMapper().
+			Append(errNoRows, maperr.WithStatus("user not found", http.StatusNotFound)),
+		maperr.NewIgnoreListMapper().
+			Append(errIgnored),
+	).WithObservers(maperr.ObserverFunc(func(o maperr.Observation) {
+		observations = append(observations, o)
+	}))
+
+	type expected struct {
+		outcome   maperr.Outcome
+		status    int
+		grpcCode  codes.Code
+		ruleIndex int
+		hasRule   bool
+	}
+	tests := []struct {
+		name     string
+		mapErr   func(err, defaultErr error) error
+		given    error
+		expected expected
+	}{
+		{
+			name: "Mapped with a nil error",
+			mapErr: func(err, defaultErr error) error {
+				return errMapper.Mapped(err, defaultErr)
+			},
+			given:    nil,
+			expected: expected{outcome: maperr.OutcomeNil},
+		},
+		{
+			name: "Mapped with a mapped error",
+			mapErr: func(err, defaultErr error) error {
+				return errMapper.Mapped(err, defaultErr)
+			},
+			given:    errNoRows,
+			expected: expected{outcome: maperr.OutcomeMapped, hasRule: true},
+		},
+		{
+			name: "MappedWithStatus with a mapped error",
+			mapErr: func(err, defaultErr error) error {
+				return errMapper.MappedWithStatus(err, defaultErr)
+			},
+			given:    errNoRows,
+			expected: expected{outcome: maperr.OutcomeMapped, status: http.StatusNotFound, hasRule: true},
+		},
+		{
+			name: "MappedWithStatus with an ignored error",
+			mapErr: func(err, defaultErr error) error {
+				return errMapper.MappedWithStatus(err, defaultErr)
+			},
+			given:    errIgnored,
+			expected: expected{outcome: maperr.OutcomeIgnored, hasRule: true},
+		},
+		{
+			name: "MappedWithStatus with an error which is not mapped",
+			mapErr: func(err, defaultErr error) error {
+				return errMapper.MappedWithStatus(err, defaultErr)
+			},
+			given:    errors.New("boom"),
+			expected: expected{outcome: maperr.OutcomeDefaulted, status: http.StatusInternalServerError},
+		},
+		{
+			name: "MappedWithGRPCStatus with a mapped error",
+			mapErr: func(err, defaultErr error) error {
+				return errMapper.MappedWithGRPCStatus(err, defaultErr)
+			},
+			given:    errNoRows,
+			expected: expected{outcome: maperr.OutcomeMapped, grpcCode: codes.NotFound, hasRule: true},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			observations = nil
+			mapped := test.mapErr(test.given, maperr.WithStatusInternalServerError)
+
+			if !assert.Len(t, observations, 1) {
+				return
+			}
+			o := observations[0]
+			assert.Equal(t, test.given, o.Err)
+			assert.Equal(t, mapped, o.Mapped)
+			assert.Equal(t, test.expected.outcome, o.Outcome)
+			assert.Equal(t, test.expected.status, o.Status)
+			assert.Equal(t, test.expected.grpcCode, o.GRPCCode)
+			if test.expected.hasRule && assert.NotNil(t, o.Rule) {
+				assert.Equal(t, test.expected.ruleIndex, o.Rule.Index)
+			} else if !test.expected.hasRule {
+				assert.Nil(t, o.Rule)
+			}
+		})
+	}
+}
+
+func TestMultiErr_WithObservers_DoesNotAlterOriginal(t *testing.T) {
+	var count int
+	counter := maperr.ObserverFunc(func(maperr.Observation) {
+		count++
+	})
+
+	errMapper := maperr.NewMultiErr()
+	observed := errMapper.WithObservers(counter)
+
+	errMapper.Mapped(errors.New("boom"), nil)
+	assert.Equal(t, 0, count)
+
+	observed.WithObservers(counter).Mapped(errors.New("boom"), nil)
+	assert.Equal(t, 2, count)
+
+	observed.Mapped(errors.New("boom"), nil)
+	assert.Equal(t, 3, count)
+}
+
+func TestMultiErr_WithObservers_StatusRequired(t *testing.T) {
+	errNoRows := errors.New("no rows")
+
+	var observations []maperr.Observation
+	errMapper := maperr.NewMultiErr(
+		maperr.NewListMapper().
+			Append(errNoRows, errors.New("user not found")),
+	).WithStatusRequired().WithObservers(maperr.ObserverFunc(func(o maperr.Observation) {
+		observations = append(observations, o)
+	}))
+
+	errMapper.MappedWithStatus(errNoRows, maperr.WithStatusInternalServerError)
+	errMapper.MappedWithGRPCStatus(errNoRows, maperr.WithCodeInternal)
+
+	if assert.Len(t, observations, 2) {
+		for _, o := range observations {
+			assert.Equal(t, maperr.OutcomeDefaulted, o.Outcome)
+			if assert.NotNil(t, o.Rule) {
+				assert.Equal(t, 0, o.Rule.Index)
+			}
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Package otelerr records the outcome of error mappings on OpenTelemetry spans
+//
+// The original cause is recorded as an exception event on the span held by the context,
+// along with the mapped code, status and rule, while the span status is only set to error
+// for server errors (5xx http statuses and their gRPC equivalents).
+// Ignored errors are never recorded.
+package otelerr
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+
+	"github.com/iZettle/maperr/v4"
+)
+
+// Attributes set on the span
+const (
+	// OutcomeKey is the outcome of the mapping, e.g.: mapped, ignored or defaulted
+	OutcomeKey = attribute.Key("maperr.outcome")
+	// RuleKey is the rule which matched the error
+	RuleKey = attribute.Key("maperr.rule")
+	// CodeKey is the machine-readable code of the mapped error
+	CodeKey = attribute.Key("maperr.code")
+	// HTTPStatusCodeKey is the http status of the mapped error
+	HTTPStatusCodeKey = attribute.Key("http.response.status_code")
+	// GRPCStatusCodeKey is the gRPC code of the mapped error
+	GRPCStatusCodeKey = attribute.Key("rpc.grpc.status_code")
+)
+
+// MappedWithStatus calls MappedWithStatusContext on the MultiErr and records the outcome on the span held by ctx
+func MappedWithStatus(ctx context.Context, m maperr.MultiErr, err, defaultErr error) maperr.ErrorWithStatusProvider {
+	var observation maperr.Observation
+	mapped := m.WithObservers(capture(&observation)).MappedWithStatusContext(ctx, err, defaultErr)
+	Record(ctx, observation)
+	return mapped
+}
+
+// MappedWithGRPCStatus calls MappedWithGRPCStatusContext on the MultiErr and records the outcome on the span held by ctx
+func MappedWithGRPCStatus(ctx context.Context, m maperr.MultiErr, err, defaultErr error) maperr.ErrorWithGRPCStatusProvider {
+	var observation maperr.Observation
+	mapped := m.WithObservers(capture(&observation)).MappedWithGRPCStatusContext(ctx, err, defaultErr)
+	Record(ctx, observation)
+	return mapped
+}
+
+// capture returns an Observer storing the observation in o
+func capture(o *maperr.Observation) maperr.Observer {
+	return maperr.ObserverFunc(func(observation maperr.Observation) {
+		*o = observation
+	})
+}
+
+// Record records an observation on the span held by ctx
+func Record(ctx context.Context, o maperr.Observation) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() || o.Outcome == maperr.OutcomeNil {
+		return
+	}
+
+	attrs := []attribute.KeyValue{
+		OutcomeKey.String(o.Outcome.String()),
+	}
+	if o.Outcome == maperr.OutcomeIgnored {
+		span.SetAttributes(attrs...)
+		return
+	}
+
+	if o.Rule != nil {
+		attrs = append(attrs, RuleKey.String(o.Rule.String()))
+	}
+	if code := codeOf(o.Mapped); code != "" {
+		attrs = append(attrs, CodeKey.String(code))
+	}
+	if o.Status != 0 {
+		attrs = append(attrs, HTTPStatusCodeKey.Int(o.Status))
+	}
+	if _, ok := o.Mapped.(maperr.ErrorWithGRPCStatusProvider); ok {
+		attrs = append(attrs, GRPCStatusCodeKey.Int64(int64(o.GRPCCode)))
+	}
+
+	span.RecordError(o.Err)
+	span.SetAttributes(attrs...)
+	if isServerError(o) {
+		span.SetStatus(otelcodes.Error, o.Mapped.Error())
+	}
+}
+
+// codeOf returns the machine-readable code of the mapped error, empty when it has none
+func codeOf(mapped error) string {
+	switch errWithCode := mapped.(type) {
+	case maperr.ErrorWithStatusProvider:
+		return errWithCode.Code()
+	case maperr.ErrorWithGRPCStatusProvider:
+		for _, detail := range errWithCode.GRPCStatus().Details() {
+			if errInfo, ok := detail.(*errdetails.ErrorInfo); ok {
+				return errInfo.Reason
+			}
+		}
+	}
+	return ""
+}
+
+// isServerError checks if the mapped error is a server error,
+// following the OpenTelemetry semantic conventions for http and gRPC server spans
+func isServerError(o maperr.Observation) bool {
+	if o.Status >= 500 {
+		return true
+	}
+	if _, ok := o.Mapped.(maperr.ErrorWithGRPCStatusProvider); !ok {
+		return false
+	}
+	switch o.GRPCCode {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		return true
+	}
+	return false
+}
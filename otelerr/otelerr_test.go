@@ -0,0 +1,169 @@
+package otelerr_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc/codes"
+
+	"github.com/iZettle/maperr/v4"
+	"github.com/iZettle/maperr/v4/otelerr"
+)
+
+var (
+	errNoRows    = errors.New("no rows")
+	errForbidden = errors.New("forbidden")
+	errIgnored   = errors.New("ignored")
+)
+
+var errMapper = maperr.NewMultiErr(
+	maperr.NewListMapper().
+		Append(errNoRows, maperr.WithStatusCode("USER_NOT_FOUND", "user not found", http.StatusNotFound)).
+		Append(errForbidden, maperr.WithCode("forbidden", codes.PermissionDenied)),
+	maperr.NewIgnoreListMapper().
+		Append(errIgnored),
+)
+
+func TestMappedWithStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		given          error
+		expectedStatus otelcodes.Code
+		expectedEvents int
+		expectedAttrs  []attribute.KeyValue
+	}{
+		{
+			name:           "no error",
+			given:          nil,
+			expectedStatus: otelcodes.Unset,
+		},
+		{
+			name:           "error mapped to a 4xx is recorded without an error status",
+			given:          maperr.Append(errors.New("select user"), errNoRows),
+			expectedStatus: otelcodes.Unset,
+			expectedEvents: 1,
+			expectedAttrs: []attribute.KeyValue{
+				otelerr.OutcomeKey.String("mapped"),
+				otelerr.RuleKey.String(`#0 error "no rows" -> "user not found"`),
+				otelerr.CodeKey.String("USER_NOT_FOUND"),
+				otelerr.HTTPStatusCodeKey.Int(http.StatusNotFound),
+			},
+		},
+		{
+			name:           "error not mapped is recorded with an error status",
+			given:          errors.New("boom"),
+			expectedStatus: otelcodes.Error,
+			expectedEvents: 1,
+			expectedAttrs: []attribute.KeyValue{
+				otelerr.OutcomeKey.String("defaulted"),
+				otelerr.HTTPStatusCodeKey.Int(http.StatusInternalServerError),
+			},
+		},
+		{
+			name:           "ignored error is not recorded",
+			given:          errIgnored,
+			expectedStatus: otelcodes.Unset,
+			expectedAttrs: []attribute.KeyValue{
+				otelerr.OutcomeKey.String("ignored"),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			span := recordSpan(t, func(ctx context.Context) {
+				otelerr.MappedWithStatus(ctx, errMapper, test.given, maperr.WithStatusInternalServerError)
+			})
+
+			assert.Equal(t, test.expectedStatus, span.Status.Code)
+			assert.Len(t, span.Events, test.expectedEvents)
+			assert.ElementsMatch(t, test.expectedAttrs, span.Attributes)
+		})
+	}
+}
+
+func TestMappedWithGRPCStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		given          error
+		expectedStatus otelcodes.Code
+		expectedAttrs  []attribute.KeyValue
+	}{
+		{
+			name:           "error mapped to a client error code",
+			given:          errForbidden,
+			expectedStatus: otelcodes.Unset,
+			expectedAttrs: []attribute.KeyValue{
+				otelerr.OutcomeKey.String("mapped"),
+				otelerr.RuleKey.String(`#1 error "forbidden" -> "forbidden"`),
+				otelerr.GRPCStatusCodeKey.Int64(int64(codes.PermissionDenied)),
+			},
+		},
+		{
+			name:           "error mapped from an http status keeps its code",
+			given:          errNoRows,
+			expectedStatus: otelcodes.Unset,
+			expectedAttrs: []attribute.KeyValue{
+				otelerr.OutcomeKey.String("mapped"),
+				otelerr.RuleKey.String(`#0 error "no rows" -> "user not found"`),
+				otelerr.CodeKey.String("USER_NOT_FOUND"),
+				otelerr.GRPCStatusCodeKey.Int64(int64(codes.NotFound)),
+			},
+		},
+		{
+			name:           "error not mapped is a server error",
+			given:          errors.New("boom"),
+			expectedStatus: otelcodes.Error,
+			expectedAttrs: []attribute.KeyValue{
+				otelerr.OutcomeKey.String("defaulted"),
+				otelerr.GRPCStatusCodeKey.Int64(int64(codes.Internal)),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			span := recordSpan(t, func(ctx context.Context) {
+				otelerr.MappedWithGRPCStatus(ctx, errMapper, test.given, maperr.WithCodeInternal)
+			})
+
+			assert.Equal(t, test.expectedStatus, span.Status.Code)
+			assert.Len(t, span.Events, 1)
+			assert.ElementsMatch(t, test.expectedAttrs, span.Attributes)
+		})
+	}
+}
+
+// recordSpan runs fn within a span and returns the exported span
+func recordSpan(t *testing.T, fn func(ctx context.Context)) tracetest.SpanStub {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	ctx, span := provider.Tracer("otelerr_test").Start(context.Background(), "handler")
+	fn(ctx)
+	span.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	return spans[0]
+}
+
+func TestMappedWithStatus_CanceledContext(t *testing.T) {
+	span := recordSpan(t, func(ctx context.Context) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		mapped := otelerr.MappedWithStatus(ctx, errMapper, errNoRows, maperr.WithStatusInternalServerError)
+		require.NotNil(t, mapped)
+		assert.Equal(t, maperr.StatusClientClosedRequest, mapped.Status())
+	})
+
+	assert.Contains(t, span.Attributes, otelerr.HTTPStatusCodeKey.Int(maperr.StatusClientClosedRequest))
+}
@@ -0,0 +1,131 @@
+package maperr
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PatternMapper is a Mapper which matches the text of errors with regular expressions or prefixes,
+// useful for errors from drivers and SDKs created with errors.New and a dynamic text
+// e.g.: pq: duplicate key value violates unique constraint "users_email_key"
+type PatternMapper struct {
+	patterns []pattern
+}
+
+// pattern matches the text of an error and builds the mapped error
+type pattern struct {
+	matcher string
+	match   func(text string) (map[string]string, bool)
+	build   func(groups map[string]string) error
+	target  error
+}
+
+// NewPatternMapper return a new PatternMapper
+func NewPatternMapper() PatternMapper {
+	return PatternMapper{}
+}
+
+// AppendRegexp maps any error whose text matches re to match
+func (pm PatternMapper) AppendRegexp(re *regexp.Regexp, match error) PatternMapper {
+	return pm.appendPattern(pattern{
+		matcher: "regexp " + strconv.Quote(re.String()),
+		match:   regexpMatcher(re),
+		target:  match,
+	})
+}
+
+// AppendRegexpFunc maps any error whose text matches re to the error returned by build,
+// called with the text captured by the named groups of re
+// e.g.: regexp.MustCompile(`unique constraint "users_(?P<field>\w+)_key"`)
+// when build returns nil the error is considered as not mapped
+func (pm PatternMapper) AppendRegexpFunc(re *regexp.Regexp, build func(groups map[string]string) error) PatternMapper {
+	return pm.appendPattern(pattern{
+		matcher: "regexp " + strconv.Quote(re.String()),
+		match:   regexpMatcher(re),
+		build:   build,
+	})
+}
+
+// AppendPrefix maps any error whose text starts with prefix to match
+func (pm PatternMapper) AppendPrefix(prefix string, match error) PatternMapper {
+	return pm.appendPattern(pattern{
+		matcher: "prefix " + strconv.Quote(prefix),
+		match: func(text string) (map[string]string, bool) {
+			return nil, strings.HasPrefix(text, prefix)
+		},
+		target: match,
+	})
+}
+
+func (pm PatternMapper) appendPattern(p pattern) PatternMapper {
+	pm.patterns = append(pm.patterns, p)
+	return pm
+}
+
+// regexpMatcher matches a text with re and returns the text captured by its named groups
+func regexpMatcher(re *regexp.Regexp) func(text string) (map[string]string, bool) {
+	return func(text string) (map[string]string, bool) {
+		submatches := re.FindStringSubmatch(text)
+		if submatches == nil {
+			return nil, false
+		}
+		groups := map[string]string{}
+		for k, name := range re.SubexpNames() {
+			if name != "" {
+				groups[name] = submatches[k]
+			}
+		}
+		return groups, true
+	}
+}
+
+// MapErr an error whose text matches one of the patterns to an error
+// every error held by err is compared, see walk for the order in which they are compared
+func (pm PatternMapper) MapErr(err error) MapResult {
+	return mapRules(err, pm)
+}
+
+// ExplainErr lists the comparisons made by MapErr
+func (pm PatternMapper) ExplainErr(err error) []Attempt {
+	return explainRules(err, pm)
+}
+
+// Rules lists the rules of the mapper, in the order they are compared
+// the target of the rules built with AppendRegexpFunc is only known once matched
+func (pm PatternMapper) Rules() []Rule {
+	rules := make([]Rule, len(pm.patterns))
+	for k := range pm.patterns {
+		rules[k] = pm.rule(k, pm.patterns[k].target)
+	}
+	return rules
+}
+
+func (pm PatternMapper) matchRule(err error) Attempt {
+	text := err.Error()
+	for k := range pm.patterns {
+		groups, ok := pm.patterns[k].match(text)
+		if !ok {
+			continue
+		}
+		target := pm.patterns[k].target
+		if pm.patterns[k].build != nil {
+			target = pm.patterns[k].build(groups)
+		}
+		if target == nil {
+			continue
+		}
+		return matched(err, pm.rule(k, target))
+	}
+	return notMatched(err, "no pattern matched")
+}
+
+// rule describes the pattern at index k
+func (pm PatternMapper) rule(k int, target error) Rule {
+	return Rule{
+		Index:   k,
+		Matcher: pm.patterns[k].matcher,
+		Target:  target,
+		Outcome: OutcomeMapped,
+	}
+}
@@ -0,0 +1,103 @@
+package maperr_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iZettle/maperr/v4"
+)
+
+func TestPatternMapper(t *testing.T) {
+	errDuplicate := errors.New(`pq: duplicate key value violates unique constraint "users_email_key"`)
+
+	errMapper := maperr.NewMultiErr(
+		maperr.NewPatternMapper().
+			AppendRegexpFunc(regexp.MustCompile(`unique constraint "users_(?P<field>\w+)_key"`), func(groups map[string]string) error {
+				return maperr.WithStatus(groups["field"]+" already exists", http.StatusConflict)
+			}).
+			AppendRegexp(regexp.MustCompile(`^pq: relation "\w+" does not exist$`), maperr.WithStatus("not ready", http.StatusServiceUnavailable)).
+			AppendPrefix("dial tcp", maperr.WithStatus("unavailable", http.StatusServiceUnavailable)),
+	)
+
+	tests := []struct {
+		name           string
+		given          error
+		expectedErr    string
+		expectedStatus int
+	}{
+		{
+			name:           "named group",
+			given:          errDuplicate,
+			expectedErr:    "email already exists",
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "regexp",
+			given:          errors.New(`pq: relation "users" does not exist`),
+			expectedErr:    "not ready",
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "prefix",
+			given:          errors.New("dial tcp 127.0.0.1:5432: connect: connection refused"),
+			expectedErr:    "unavailable",
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "error held by a combined error",
+			given:          maperr.Combine(errors.New("insert user"), errDuplicate, errors.New("rollback")),
+			expectedErr:    "email already exists",
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "wrapped error",
+			given:          fmt.Errorf("insert user: %w", errDuplicate),
+			expectedErr:    "email already exists",
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "not matched",
+			given:          errors.New(`pq: syntax error at or near "SELEC"`),
+			expectedErr:    "Internal Server Error",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := errMapper.MappedWithStatus(test.given, maperr.WithStatusInternalServerError)
+
+			require.NotNil(t, actual)
+			assert.EqualError(t, actual, test.expectedErr)
+			assert.Equal(t, test.expectedStatus, actual.Status())
+		})
+	}
+}
+
+func TestPatternMapper_BuildReturnsNil(t *testing.T) {
+	mapper := maperr.NewPatternMapper().
+		AppendRegexpFunc(regexp.MustCompile(`constraint "(?P<name>\w+)"`), func(groups map[string]string) error {
+			return nil
+		}).
+		AppendPrefix("pq:", errors.New("database error"))
+
+	res := mapper.MapErr(errors.New(`pq: violates constraint "users_pkey"`))
+
+	require.NotNil(t, res)
+	assert.EqualError(t, res.Last(), "database error")
+}
+
+func TestPatternMapper_ExplainErr(t *testing.T) {
+	mapper := maperr.NewPatternMapper().
+		AppendPrefix("dial tcp", errors.New("unavailable"))
+
+	attempts := mapper.ExplainErr(errors.New("dial tcp: i/o timeout"))
+
+	require.Len(t, attempts, 1)
+	assert.Equal(t, `"dial tcp: i/o timeout" matched rule #0 prefix "dial tcp" -> "unavailable"`, attempts[0].String())
+}
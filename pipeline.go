@@ -0,0 +1,83 @@
+package maperr
+
+import "context"
+
+// MapErr maps an error with the mappers of the MultiErr, using its precedence policy,
+// so that a MultiErr can be used as a Mapper of another MultiErr
+// the observers are not notified, as no default error is involved
+func (m MultiErr) MapErr(err error) MapResult {
+	res, _ := m.lastMapped(err)
+	return res
+}
+
+// MapErrContext behaves like MapErr, passing ctx to the mappers implementing ContextMapper
+func (m MultiErr) MapErrContext(ctx context.Context, err error) MapResult {
+	res, _ := m.lastMappedContext(ctx, err)
+	return res
+}
+
+// ExplainErr lists the comparisons made by the mappers of the MultiErr which implement Explainer
+func (m MultiErr) ExplainErr(err error) []Attempt {
+	var attempts []Attempt
+	for _, trace := range m.Explain(err).Mappers {
+		attempts = append(attempts, trace.Attempts...)
+	}
+	return attempts
+}
+
+// Pipeline is a Mapper which maps an error through layers,
+// e.g.: from storage errors to domain errors, then from domain errors to http errors
+type Pipeline struct {
+	stages []Mapper
+}
+
+// NewPipeline returns a Pipeline running the stages in order,
+// each stage mapping the error resulting from the previous one
+func NewPipeline(stages ...Mapper) Pipeline {
+	return Pipeline{
+		stages: stages,
+	}
+}
+
+// MapErr maps the error with every stage and returns the result of the last stage which matched,
+// whose Apply holds the whole chain of mapped errors
+// the pipeline stops when a stage ignores the error
+func (p Pipeline) MapErr(err error) MapResult {
+	return p.MapErrContext(context.Background(), err)
+}
+
+// MapErrContext behaves like MapErr, passing ctx to the stages implementing ContextMapper
+func (p Pipeline) MapErrContext(ctx context.Context, err error) MapResult {
+	var res MapResult
+	for k := range p.stages {
+		staged := mapErrContext(ctx, p.stages[k], err)
+		if !isMatched(staged) {
+			continue
+		}
+		res = staged
+		if staged.Outcome() == OutcomeIgnored {
+			return res
+		}
+		err = staged.Apply()
+	}
+	return res
+}
+
+// ExplainErr lists the comparisons made by the stages which implement Explainer, in the order they were made
+func (p Pipeline) ExplainErr(err error) []Attempt {
+	var attempts []Attempt
+	for k := range p.stages {
+		if explainer, ok := p.stages[k].(Explainer); ok {
+			attempts = append(attempts, explainer.ExplainErr(err)...)
+		}
+		staged := p.stages[k].MapErr(err)
+		if !isMatched(staged) {
+			continue
+		}
+		if staged.Outcome() == OutcomeIgnored {
+			return attempts
+		}
+		err = staged.Apply()
+	}
+	return attempts
+}
@@ -0,0 +1,146 @@
+package maperr_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iZettle/maperr/v4"
+)
+
+func TestMultiErr_AsMapper(t *testing.T) {
+	errNoRows := errors.New("no rows")
+	errCanceled := errors.New("canceled")
+
+	baseMapper := maperr.NewMultiErr(
+		maperr.NewIgnoreListMapper().
+			Append(errCanceled),
+	)
+	errMapper := maperr.NewMultiErr(
+		baseMapper,
+		maperr.NewHashableMapper().
+			Append(errNoRows, maperr.WithStatus("user not found", http.StatusNotFound)),
+	)
+
+	notFound := errMapper.MappedWithStatus(errNoRows, maperr.WithStatusInternalServerError)
+	require.NotNil(t, notFound)
+	assert.Equal(t, http.StatusNotFound, notFound.Status())
+
+	assert.Nil(t, errMapper.MappedWithStatus(errCanceled, maperr.WithStatusInternalServerError))
+
+	explanation := errMapper.Explain(errCanceled)
+	assert.Equal(t, maperr.OutcomeIgnored, explanation.Outcome)
+	require.Len(t, explanation.Mappers, 1)
+	require.Len(t, explanation.Mappers[0].Attempts, 1)
+	assert.True(t, explanation.Mappers[0].Attempts[0].Matched)
+}
+
+func TestPipeline(t *testing.T) {
+	layerOneFailed := errors.New("layer 1 failed")
+	layerTwoFailed := errors.New("layer 2 failed")
+	layerThreeFailed := maperr.WithStatus("layer 3 failed", http.StatusConflict)
+	errCanceled := errors.New("canceled")
+
+	storageToDomain := maperr.NewMultiErr(
+		maperr.NewHashableMapper().
+			Append(layerOneFailed, layerTwoFailed),
+		maperr.NewIgnoreListMapper().
+			Append(errCanceled),
+	)
+	domainToHTTP := maperr.NewMultiErr(
+		maperr.NewHashableMapper().
+			Append(layerTwoFailed, layerThreeFailed),
+	)
+	errMapper := maperr.NewMultiErr(maperr.NewPipeline(storageToDomain, domainToHTTP))
+
+	tests := []struct {
+		name           string
+		given          error
+		expectedErr    string
+		expectedStatus int
+	}{
+		{
+			name:           "error going through three layers",
+			given:          layerOneFailed,
+			expectedErr:    "layer 1 failed; layer 2 failed; layer 3 failed",
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "error mapped by the last stage only",
+			given:          layerTwoFailed,
+			expectedErr:    "layer 2 failed; layer 3 failed",
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:  "error ignored by the first stage",
+			given: errCanceled,
+		},
+		{
+			name:           "error not mapped by any stage",
+			given:          errors.New("boom"),
+			expectedErr:    "boom; Internal Server Error",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mapped := errMapper.Mapped(test.given, maperr.WithStatusInternalServerError)
+			if test.expectedErr == "" {
+				assert.NoError(t, mapped)
+				return
+			}
+			assert.EqualError(t, mapped, test.expectedErr)
+
+			withStatus := errMapper.MappedWithStatus(test.given, maperr.WithStatusInternalServerError)
+			require.NotNil(t, withStatus)
+			assert.Equal(t, test.expectedStatus, withStatus.Status())
+		})
+	}
+}
+
+func TestPipeline_WrapWith(t *testing.T) {
+	errDomain := errors.New("user missing")
+
+	toDomain := maperr.NewListMapper().
+		Appendf("user %s missing", maperr.WrapWith(errDomain))
+	toHTTP := []maperr.Mapper{
+		maperr.NewHashableMapper().
+			Append(errDomain, maperr.WithStatus("user not found", http.StatusNotFound)),
+		maperr.NewRegistryMapper().
+			Register(errDomain, maperr.WithStatus("user not found", http.StatusNotFound)),
+	}
+
+	for _, mapper := range toHTTP {
+		// the first mapper compares the wrapped error itself, as none of the errors it holds match
+		errMapper := maperr.NewMultiErr(maperr.NewPipeline(toDomain, maperr.NewHashableMapper().
+			Append(errors.New("unrelated"), errors.New("unrelated")), mapper))
+
+		var actual maperr.ErrorWithStatusProvider
+		require.NotPanics(t, func() {
+			actual = errMapper.MappedWithStatus(maperr.Errorf("user %s missing", "bob"), nil)
+		})
+		require.NotNil(t, actual)
+		assert.Equal(t, http.StatusNotFound, actual.Status())
+		assert.EqualError(t, actual, "user not found")
+	}
+}
+
+func TestPipeline_ExplainErr(t *testing.T) {
+	layerOneFailed := errors.New("layer 1 failed")
+	layerTwoFailed := errors.New("layer 2 failed")
+
+	pipeline := maperr.NewPipeline(
+		maperr.NewHashableMapper().
+			Append(layerOneFailed, layerTwoFailed),
+		maperr.NewHashableMapper().
+			Append(layerTwoFailed, errors.New("layer 3 failed")),
+	)
+
+	attempts := pipeline.ExplainErr(layerOneFailed)
+	require.Len(t, attempts, 2)
+	assert.Equal(t, `"layer 1 failed" matched rule error "layer 1 failed" -> "layer 2 failed"`, attempts[0].String())
+	assert.Equal(t, `"layer 2 failed" matched rule error "layer 2 failed" -> "layer 3 failed"`, attempts[1].String())
+}
@@ -0,0 +1,89 @@
+package maperr
+
+import (
+	"context"
+	"errors"
+	"math"
+)
+
+// Precedence decides which result wins when several mappers of a MultiErr match an error
+type Precedence int
+
+// Precedence policies of a MultiErr
+const (
+	// PrecedenceMapperOrder the first mapper matching any of the errors wins,
+	// even when a later mapper matches a more recent error, this is the default
+	PrecedenceMapperOrder Precedence = iota
+	// PrecedenceNewestError the mapper matching the most recent error wins, see walk for the order of the errors,
+	// mapper order breaks ties and results which do not implement MatchProvider are considered the oldest
+	PrecedenceNewestError
+	// PrecedenceHighestStatus the mapper mapping to the highest http status wins,
+	// mapper order breaks ties and results with no status are considered the lowest
+	PrecedenceHighestStatus
+)
+
+// String returns a human readable representation of the precedence
+func (p Precedence) String() string {
+	switch p {
+	case PrecedenceMapperOrder:
+		return "mapper order"
+	case PrecedenceNewestError:
+		return "newest error"
+	case PrecedenceHighestStatus:
+		return "highest status"
+	}
+	return "unknown"
+}
+
+// WithPrecedence returns a copy of the MultiErr using the precedence policy
+// to decide which mapper wins when several match an error
+func (m MultiErr) WithPrecedence(precedence Precedence) MultiErr {
+	m.precedence = precedence
+	return m
+}
+
+// pick returns the winning result among the results of every mapper, in mapper order,
+// along with the position of its mapper, -1 when none matched
+func (p Precedence) pick(results []MapResult) (MapResult, int) {
+	var picked MapResult
+	var pickedRank int
+	pickedMapper := -1
+	for k, res := range results {
+		if !isMatched(res) {
+			continue
+		}
+		rank := p.rank(res)
+		if picked == nil || rank > pickedRank {
+			picked, pickedRank, pickedMapper = res, rank, k
+		}
+	}
+	return picked, pickedMapper
+}
+
+// rank returns how a result ranks for the policy, the highest rank wins
+func (p Precedence) rank(res MapResult) int {
+	switch p {
+	case PrecedenceNewestError:
+		if provider, ok := res.(MatchProvider); ok {
+			if match, ok := provider.Match(); ok {
+				return -match.Position
+			}
+		}
+		return math.MinInt
+	case PrecedenceHighestStatus:
+		var errWithStatus ErrorWithStatusProvider
+		if errors.As(res.Last(), &errWithStatus) {
+			return errWithStatus.Status()
+		}
+	}
+	return 0
+}
+
+// mapErr maps err with every mapper and picks the winning result
+func (p Precedence) mapErr(ctx context.Context, mappers mapperList, err error) (MapResult, int) {
+	results := make([]MapResult, len(mappers))
+	for k := range mappers {
+		results[k] = mapErrContext(ctx, mappers[k], err)
+	}
+	return p.pick(results)
+}
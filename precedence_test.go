@@ -0,0 +1,130 @@
+package maperr_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iZettle/maperr/v4"
+)
+
+func TestMultiErr_WithPrecedence(t *testing.T) {
+	errOld := errors.New("old")
+	errNew := errors.New("new")
+
+	mappers := []maperr.Mapper{
+		maperr.NewHashableMapper().
+			Append(errOld, maperr.WithStatus("old error", http.StatusNotFound)),
+		maperr.NewHashableMapper().
+			Append(errNew, maperr.WithStatus("new error", http.StatusConflict)),
+		maperr.NewHashableMapper().
+			Append(errOld, maperr.WithStatus("old failure", http.StatusServiceUnavailable)),
+	}
+
+	tests := []struct {
+		name           string
+		precedence     maperr.Precedence
+		given          error
+		expectedErr    string
+		expectedStatus int
+	}{
+		{
+			name:           "mapper order",
+			precedence:     maperr.PrecedenceMapperOrder,
+			given:          maperr.Combine(errOld, errNew),
+			expectedErr:    "old error",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "newest error",
+			precedence:     maperr.PrecedenceNewestError,
+			given:          maperr.Combine(errOld, errNew),
+			expectedErr:    "new error",
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "newest error, ties broken by mapper order",
+			precedence:     maperr.PrecedenceNewestError,
+			given:          maperr.Combine(errNew, errOld),
+			expectedErr:    "old error",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "highest status",
+			precedence:     maperr.PrecedenceHighestStatus,
+			given:          maperr.Combine(errOld, errNew),
+			expectedErr:    "old failure",
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "highest status, single match",
+			precedence:     maperr.PrecedenceHighestStatus,
+			given:          errNew,
+			expectedErr:    "new error",
+			expectedStatus: http.StatusConflict,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errMapper := maperr.NewMultiErr(mappers...).WithPrecedence(test.precedence)
+
+			actual := errMapper.MappedWithStatus(test.given, maperr.WithStatusInternalServerError)
+			require.NotNil(t, actual)
+			assert.EqualError(t, actual, test.expectedErr)
+			assert.Equal(t, test.expectedStatus, actual.Status())
+
+			explanation := errMapper.Explain(test.given)
+			require.NotNil(t, explanation.Result)
+			assert.EqualError(t, explanation.Result.Last(), test.expectedErr)
+		})
+	}
+}
+
+func TestMultiErr_WithPrecedence_IgnoredNewestError(t *testing.T) {
+	errOld := errors.New("old")
+	errCanceled := errors.New("canceled")
+
+	errMapper := maperr.NewMultiErr(
+		maperr.NewHashableMapper().
+			Append(errOld, maperr.WithStatus("old error", http.StatusNotFound)),
+		maperr.NewIgnoreListMapper().
+			Append(errCanceled),
+	)
+
+	given := maperr.Combine(errOld, errCanceled)
+	assert.NotNil(t, errMapper.MappedWithStatus(given, nil))
+	assert.Nil(t, errMapper.WithPrecedence(maperr.PrecedenceNewestError).MappedWithStatus(given, nil))
+}
+
+func TestMultiErr_WithPrecedence_TypeMapper(t *testing.T) {
+	errNewest := errors.New("newest")
+
+	errMapper := maperr.NewMultiErr(
+		maperr.NewListMapper().
+			Append(errNewest, errors.New("from newest")),
+		maperr.NewTypeMapper(func(err *codeError) error {
+			return errors.New("from oldest typed")
+		}),
+	).WithPrecedence(maperr.PrecedenceNewestError)
+
+	assert.EqualError(t, errMapper.MapErr(maperr.Combine(&codeError{code: 1}, errNewest)).Last(), "from newest")
+	assert.EqualError(t, errMapper.MapErr(maperr.CombineJoin(&codeError{code: 1}, errNewest)).Last(), "from newest")
+}
+
+func TestListMapper_Match(t *testing.T) {
+	errNoRows := errors.New("no rows")
+
+	res := maperr.NewListMapper().
+		Append(errNoRows, errors.New("user not found")).
+		MapErr(maperr.Combine(errNoRows, errors.New("rollback"), errors.New("close")))
+
+	provider, ok := res.(maperr.MatchProvider)
+	require.True(t, ok)
+	match, ok := provider.Match()
+	require.True(t, ok)
+	assert.Equal(t, 2, match.Position)
+	assert.Equal(t, errNoRows, match.Err)
+}
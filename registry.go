@@ -0,0 +1,191 @@
+package maperr
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// RegistryMapper is a Mapper whose rules can be registered and unregistered at runtime,
+// e.g.: by plugins registering their errors after startup, while errors are being mapped
+//
+// It is safe for concurrent use: every change builds a new snapshot of the rules,
+// which is swapped atomically, so mapping an error never takes a lock
+// A RegistryMapper must not be copied after first use
+type RegistryMapper struct {
+	mu       sync.Mutex
+	snapshot atomic.Pointer[registrySnapshot]
+}
+
+// registrySnapshot is an immutable set of rules
+type registrySnapshot struct {
+	// hashable holds the rules of hashable keys, compared by identity like HashableMapper does
+	hashable map[error]Rule
+	// list holds the rules of formats and not hashable keys, compared in order like ListMapper does
+	list []Rule
+}
+
+// NewRegistryMapper returns an empty RegistryMapper
+func NewRegistryMapper() *RegistryMapper {
+	return &RegistryMapper{}
+}
+
+// Register maps err to match, replacing the rule already registered for err
+// formatted errors are compared by format like ListMapper does, other hashable errors by identity
+// it panics if err is nil, as no error could ever match it
+func (r *RegistryMapper) Register(err, match error) *RegistryMapper {
+	return r.register(err, match, OutcomeMapped)
+}
+
+// Registerf maps errors created with the format to match, replacing the rule already registered for the format
+func (r *RegistryMapper) Registerf(format string, match error) *RegistryMapper {
+	return r.register(newUnformattedError(format), match, OutcomeMapped)
+}
+
+// Ignore ignores err, replacing the rule already registered for err, it panics if err is nil
+func (r *RegistryMapper) Ignore(err error) *RegistryMapper {
+	return r.register(err, nil, OutcomeIgnored)
+}
+
+// Unregister removes the rule registered for err, it returns false when there was none
+func (r *RegistryMapper) Unregister(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snapshot := r.load().clone()
+	key, hashable := registryKey(err)
+	if hashable {
+		if _, ok := snapshot.hashable[key]; !ok {
+			return false
+		}
+		delete(snapshot.hashable, key)
+	} else {
+		k := snapshot.indexOf(key)
+		if k < 0 {
+			return false
+		}
+		snapshot.list = append(snapshot.list[:k], snapshot.list[k+1:]...)
+	}
+	r.snapshot.Store(snapshot)
+	return true
+}
+
+func (r *RegistryMapper) register(err, match error, outcome Outcome) *RegistryMapper {
+	if err == nil {
+		panic("maperr: nil error registered in a RegistryMapper")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snapshot := r.load().clone()
+	key, hashable := registryKey(err)
+	rule := Rule{
+		Index:   -1,
+		Key:     key,
+		Format:  formatOf(key),
+		Target:  match,
+		Outcome: outcome,
+	}
+	if hashable {
+		snapshot.hashable[key] = rule
+	} else if k := snapshot.indexOf(key); k >= 0 {
+		snapshot.list[k] = rule
+	} else {
+		snapshot.list = append(snapshot.list, rule)
+	}
+	r.snapshot.Store(snapshot)
+	return r
+}
+
+// load returns the current snapshot, an empty one when nothing was registered yet
+func (r *RegistryMapper) load() *registrySnapshot {
+	if snapshot := r.snapshot.Load(); snapshot != nil {
+		return snapshot
+	}
+	return &registrySnapshot{}
+}
+
+// registryKey returns the key under which err is registered,
+// and whether it is compared by identity
+func registryKey(err error) (error, bool) {
+	if formatOf(err) != "" {
+		return castError(err), false
+	}
+	key := hashableKey(err)
+	if isHashable(key) {
+		return key, true
+	}
+	return castError(err), false
+}
+
+// MapErr an error to the error registered for it
+// every error held by err is compared, see walk for the order in which they are compared
+func (r *RegistryMapper) MapErr(err error) MapResult {
+	return mapRules(err, r.load())
+}
+
+// ExplainErr lists the comparisons made by MapErr
+func (r *RegistryMapper) ExplainErr(err error) []Attempt {
+	return explainRules(err, r.load())
+}
+
+// Rules lists the registered rules, the hashable ones first as they are compared first
+func (r *RegistryMapper) Rules() []Rule {
+	snapshot := r.load()
+	rules := make([]Rule, 0, len(snapshot.hashable)+len(snapshot.list))
+	for _, rule := range snapshot.hashable {
+		rules = append(rules, rule)
+	}
+	sortByKey(rules)
+	for k := range snapshot.list {
+		rules = append(rules, snapshot.rule(k))
+	}
+	return rules
+}
+
+func (s *registrySnapshot) clone() *registrySnapshot {
+	cpy := &registrySnapshot{
+		hashable: make(map[error]Rule, len(s.hashable)),
+		list:     append([]Rule(nil), s.list...),
+	}
+	for key, rule := range s.hashable {
+		cpy.hashable[key] = rule
+	}
+	return cpy
+}
+
+// indexOf returns the index of the rule registered for key in the list, -1 when there is none
+func (s *registrySnapshot) indexOf(key error) int {
+	for k := range s.list {
+		if castError(s.list[k].Key).Equal(key) {
+			return k
+		}
+	}
+	return -1
+}
+
+// rule returns the rule at index k of the list
+func (s *registrySnapshot) rule(k int) Rule {
+	rule := s.list[k]
+	rule.Index = k
+	return rule
+}
+
+func (s *registrySnapshot) matchRule(err error) Attempt {
+	if key := hashableKey(err); isHashable(key) {
+		if rule, ok := s.hashable[key]; ok {
+			return matched(err, rule)
+		}
+	}
+	comparableErr := castError(err)
+	for k := range s.list {
+		if comparableErr.Equal(s.list[k].Key) {
+			return matched(err, s.rule(k))
+		}
+	}
+	return notMatched(err, "no rule matched")
+}
@@ -0,0 +1,151 @@
+package maperr_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iZettle/maperr/v4"
+)
+
+func TestRegistryMapper(t *testing.T) {
+	errNoRows := errors.New("no rows")
+	errCanceled := errors.New("canceled")
+	errUserNotFound := maperr.WithStatus("user not found", http.StatusNotFound)
+	errUserLocked := maperr.WithStatus("user is locked", http.StatusLocked)
+
+	registry := maperr.NewRegistryMapper().
+		Register(errNoRows, errUserNotFound).
+		Registerf("user %s is locked", errUserLocked).
+		Ignore(errCanceled)
+	errMapper := maperr.NewMultiErr(registry)
+
+	tests := []struct {
+		name           string
+		given          error
+		expectedErr    string
+		expectedStatus int
+	}{
+		{
+			name:           "hashable error",
+			given:          fmt.Errorf("select user: %w", errNoRows),
+			expectedErr:    "user not found",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "formatted error",
+			given:          maperr.Errorf("user %s is locked", "bob"),
+			expectedErr:    "user is locked",
+			expectedStatus: http.StatusLocked,
+		},
+		{
+			name:  "ignored error",
+			given: maperr.Combine(errors.New("rollback"), errCanceled),
+		},
+		{
+			name:           "error not registered",
+			given:          errors.New("no rows"),
+			expectedErr:    "Internal Server Error",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := errMapper.MappedWithStatus(test.given, maperr.WithStatusInternalServerError)
+			if test.expectedErr == "" {
+				assert.Nil(t, actual)
+				return
+			}
+			require.NotNil(t, actual)
+			assert.EqualError(t, actual, test.expectedErr)
+			assert.Equal(t, test.expectedStatus, actual.Status())
+		})
+	}
+}
+
+func TestRegistryMapper_RegisterReplacesAndUnregisterRemoves(t *testing.T) {
+	errNoRows := errors.New("no rows")
+
+	registry := maperr.NewRegistryMapper().
+		Register(errNoRows, errors.New("user not found")).
+		Registerf("user %s is locked", errors.New("locked"))
+
+	registry.Register(errNoRows, errors.New("user does not exist"))
+	registry.Registerf("user %s is locked", errors.New("user is locked"))
+	assert.Len(t, registry.Rules(), 2)
+	assert.EqualError(t, registry.MapErr(errNoRows).Last(), "user does not exist")
+	assert.EqualError(t, registry.MapErr(maperr.Errorf("user %s is locked", "bob")).Last(), "user is locked")
+
+	assert.True(t, registry.Unregister(errNoRows))
+	assert.True(t, registry.Unregister(maperr.Errorf("user %s is locked", "")))
+	assert.False(t, registry.Unregister(errNoRows))
+	assert.Empty(t, registry.Rules())
+	assert.Nil(t, registry.MapErr(errNoRows))
+}
+
+func TestRegistryMapper_ExplainErr(t *testing.T) {
+	errNoRows := errors.New("no rows")
+
+	registry := maperr.NewRegistryMapper().
+		Registerf("user %s is locked", errors.New("user is locked")).
+		Register(errNoRows, errors.New("user not found"))
+
+	attempts := registry.ExplainErr(maperr.Combine(errNoRows, maperr.Errorf("user %s is locked", "bob")))
+
+	require.Len(t, attempts, 1)
+	assert.Equal(t, `"user bob is locked" matched rule #0 format "user %s is locked" -> "user is locked"`, attempts[0].String())
+}
+
+func TestRegistryMapper_RegisterNil(t *testing.T) {
+	registry := maperr.NewRegistryMapper()
+	assert.Panics(t, func() {
+		registry.Register(nil, errors.New("user not found"))
+	})
+	assert.Panics(t, func() {
+		registry.Ignore(nil)
+	})
+
+	// a rejected nil key does not break the rules registered afterwards
+	registry.Register(maperr.Errorf("user %s is locked", ""), errors.New("user is locked"))
+	assert.NotNil(t, registry.MapErr(maperr.Errorf("user %s is locked", "bob")))
+	assert.False(t, registry.Unregister(nil))
+}
+
+func TestRegistryMapper_Concurrent(t *testing.T) {
+	const workers = 8
+	const iterations = 50
+
+	errNoRows := errors.New("no rows")
+	registry := maperr.NewRegistryMapper().
+		Register(errNoRows, errors.New("user not found"))
+	errMapper := maperr.NewMultiErr(registry)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(2)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				pluginErr := fmt.Errorf("plugin %d error %d", w, i)
+				registry.Register(pluginErr, errors.New("plugin failed"))
+				registry.Registerf(fmt.Sprintf("plugin %d format %d %%s", w, i), errors.New("plugin failed"))
+				registry.Unregister(pluginErr)
+			}
+		}(w)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				assert.EqualError(t, errMapper.Mapped(errNoRows, nil), "no rows; user not found")
+				_ = registry.Rules()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, registry.Rules(), 1+workers*iterations)
+}
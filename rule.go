@@ -0,0 +1,142 @@
+package maperr
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Rule describes a rule of a Mapper, matching errors and mapping them to a Target
+type Rule struct {
+	// Index is the position of the rule within its Mapper, -1 when rules are not ordered
+	Index int
+	// Key is the error matched by the rule, nil when the rule matches errors with a function
+	Key error
+	// Format is the format matched by the rule, set when the rule compares formatted errors
+	Format string
+	// Matcher describes how the rule matches errors when it has no Key
+	// e.g.: "func" or "type *json.SyntaxError"
+	Matcher string
+	// Target is the error matched errors are mapped to, nil for ignore rules
+	Target error
+	// Outcome is what the rule does with the errors it matches
+	Outcome Outcome
+}
+
+// String returns a human readable representation of the rule
+// e.g.: #1 format "user %s not found" -> "user does not exist"
+func (r Rule) String() string {
+	var key string
+	switch {
+	case r.Format != "":
+		key = "format " + strconv.Quote(r.Format)
+	case r.Key != nil:
+		key = "error " + strconv.Quote(r.Key.Error())
+	default:
+		key = r.Matcher
+	}
+
+	target := r.Outcome.String()
+	if r.Target != nil {
+		target = strconv.Quote(r.Target.Error())
+	}
+
+	if r.Index < 0 {
+		return fmt.Sprintf("%s -> %s", key, target)
+	}
+	return fmt.Sprintf("#%d %s -> %s", r.Index, key, target)
+}
+
+// RuleProvider is implemented by the MapResult which know the Rule that matched the error
+type RuleProvider interface {
+	Rule() (Rule, bool)
+}
+
+// Match describes which of the errors held by the mapped error was matched
+type Match struct {
+	// Err is the error which was matched
+	Err error
+	// Position is the position of Err in the order errors are compared, 0 being the most recent error
+	Position int
+}
+
+// MatchProvider is implemented by the MapResult which know which error was matched
+type MatchProvider interface {
+	Match() (Match, bool)
+}
+
+// Attempt describes the comparison of an error, held by the error being mapped, with the rules of a Mapper
+type Attempt struct {
+	// Err is the error which was compared
+	Err error
+	// Matched is true when one of the rules matched Err
+	Matched bool
+	// Rule is the rule which matched Err, zero value when not matched
+	Rule Rule
+	// Reason explains why no rule matched Err
+	Reason string
+}
+
+// String returns a human readable representation of the attempt
+func (a Attempt) String() string {
+	if a.Matched {
+		return fmt.Sprintf("%q matched rule %s", a.Err.Error(), a.Rule)
+	}
+	return fmt.Sprintf("%q not matched: %s", a.Err.Error(), a.Reason)
+}
+
+// ruleMatcher is implemented by the mappers which compare every error of the tree with a list of rules
+type ruleMatcher interface {
+	// matchRule compares a single error with the rules, without following its Unwrap() chain
+	matchRule(err error) Attempt
+}
+
+// mapRules maps err using the first rule matching one of the errors of its tree
+func mapRules(err error, rm ruleMatcher) MapResult {
+	var res MapResult
+	position := 0
+	walk(err, func(wrapped error) bool {
+		attempt := rm.matchRule(wrapped)
+		if attempt.Matched {
+			res = newRuleResult(err, attempt.Rule, Match{Err: wrapped, Position: position})
+		}
+		position++
+		return attempt.Matched
+	})
+	return res
+}
+
+// explainRules lists the comparisons made by mapRules
+func explainRules(err error, rm ruleMatcher) []Attempt {
+	var attempts []Attempt
+	walk(err, func(wrapped error) bool {
+		attempt := rm.matchRule(wrapped)
+		attempts = append(attempts, attempt)
+		return attempt.Matched
+	})
+	return attempts
+}
+
+// newRuleResult returns the MapResult for an error matched by a rule
+func newRuleResult(err error, rule Rule, match Match) MapResult {
+	if rule.Outcome == OutcomeIgnored {
+		return NewIgnoreStrategy(err).WithRule(rule).WithMatch(match)
+	}
+	return newStrategy(err, rule.Target, rule, match)
+}
+
+// notMatched returns an Attempt for an error which was not matched
+func notMatched(err error, reason string) Attempt {
+	return Attempt{
+		Err:    err,
+		Reason: reason,
+	}
+}
+
+// matched returns an Attempt for an error which was matched by rule
+func matched(err error, rule Rule) Attempt {
+	return Attempt{
+		Err:     err,
+		Matched: true,
+		Rule:    rule,
+	}
+}
@@ -0,0 +1,327 @@
+package maperr
+
+// AppendStrategy is a MapResult which appends the mapped error
+// to the error that has been mapped
+type AppendStrategy struct {
+	details
+	previousErr error
+	lastErr     error
+	combiner    Combiner
+}
+
+// NewAppendStrategy instantiates a new AppendStrategy
+func NewAppendStrategy(previous, last error) AppendStrategy {
+	return AppendStrategy{previousErr: previous, lastErr: last}
+}
+
+// Previous returns the error that we want to append to
+func (as AppendStrategy) Previous() error {
+	return as.previousErr
+}
+
+// Last returns the error that we are appending
+func (as AppendStrategy) Last() error {
+	return as.lastErr
+}
+
+// Apply the append strategy by appending previousErr to lastErr
+// errors are combined with go.uber.org/multierr unless another Combiner is set with WithCombiner
+func (as AppendStrategy) Apply() error {
+	if as.lastErr == nil {
+		return nil
+	}
+	if as.combiner == nil {
+		return Append(as.previousErr, as.lastErr)
+	}
+	return as.combiner.Append(as.previousErr, as.lastErr)
+}
+
+// Outcome returns OutcomeMapped
+func (as AppendStrategy) Outcome() Outcome {
+	return OutcomeMapped
+}
+
+// WithRule returns a copy of the strategy holding the rule which matched the error
+func (as AppendStrategy) WithRule(rule Rule) AppendStrategy {
+	as.details = as.details.withRule(rule)
+	return as
+}
+
+// WithMatch returns a copy of the strategy holding which of the errors was matched
+func (as AppendStrategy) WithMatch(match Match) AppendStrategy {
+	as.details = as.details.withMatch(match)
+	return as
+}
+
+// WithCombiner returns a copy of the strategy which combines the errors using c
+func (as AppendStrategy) WithCombiner(c Combiner) AppendStrategy {
+	as.combiner = c
+	return as
+}
+
+// IgnoreStrategy is a MapResult which drops the error that has been mapped
+type IgnoreStrategy struct {
+	details
+	previousErr error
+}
+
+// NewIgnoreStrategy instantiates a new IgnoreStrategy
+func NewIgnoreStrategy(previous error) IgnoreStrategy {
+	return IgnoreStrategy{
+		previousErr: previous,
+	}
+}
+
+// Previous holds the error that we wanted to ignore
+func (is IgnoreStrategy) Previous() error {
+	return is.previousErr
+}
+
+// Last is defined to implement the interface
+// returns nil since we are always mapping to nil for this strategy
+func (is IgnoreStrategy) Last() error {
+	return nil
+}
+
+// Apply is defined to implement the interface
+// returns nil since we are always mapping to nil for this strategy
+func (is IgnoreStrategy) Apply() error {
+	return nil
+}
+
+// Outcome returns OutcomeIgnored
+func (is IgnoreStrategy) Outcome() Outcome {
+	return OutcomeIgnored
+}
+
+// WithRule returns a copy of the strategy holding the rule which matched the error
+func (is IgnoreStrategy) WithRule(rule Rule) IgnoreStrategy {
+	is.details = is.details.withRule(rule)
+	return is
+}
+
+// WithMatch returns a copy of the strategy holding which of the errors was matched
+func (is IgnoreStrategy) WithMatch(match Match) IgnoreStrategy {
+	is.details = is.details.withMatch(match)
+	return is
+}
+
+// ReplaceStrategy is a MapResult which replaces the error that has been mapped
+// by the mapped error, dropping the cause
+type ReplaceStrategy struct {
+	details
+	previousErr error
+	lastErr     error
+}
+
+// NewReplaceStrategy instantiates a new ReplaceStrategy
+func NewReplaceStrategy(previous, last error) ReplaceStrategy {
+	return ReplaceStrategy{previousErr: previous, lastErr: last}
+}
+
+// Previous returns the error that is replaced
+func (rs ReplaceStrategy) Previous() error {
+	return rs.previousErr
+}
+
+// Last returns the error replacing the previous one
+func (rs ReplaceStrategy) Last() error {
+	return rs.lastErr
+}
+
+// Apply the replace strategy by returning lastErr only
+func (rs ReplaceStrategy) Apply() error {
+	return rs.lastErr
+}
+
+// Outcome returns OutcomeMapped
+func (rs ReplaceStrategy) Outcome() Outcome {
+	return OutcomeMapped
+}
+
+// WithRule returns a copy of the strategy holding the rule which matched the error
+func (rs ReplaceStrategy) WithRule(rule Rule) ReplaceStrategy {
+	rs.details = rs.details.withRule(rule)
+	return rs
+}
+
+// WithMatch returns a copy of the strategy holding which of the errors was matched
+func (rs ReplaceStrategy) WithMatch(match Match) ReplaceStrategy {
+	rs.details = rs.details.withMatch(match)
+	return rs
+}
+
+// WrapStrategy is a MapResult which wraps the error that has been mapped
+// within the mapped error, only the message of the mapped error is kept
+// while the cause is still available through errors.Is and errors.As
+type WrapStrategy struct {
+	details
+	previousErr error
+	lastErr     error
+}
+
+// NewWrapStrategy instantiates a new WrapStrategy
+func NewWrapStrategy(previous, last error) WrapStrategy {
+	return WrapStrategy{previousErr: previous, lastErr: last}
+}
+
+// Previous returns the error that is wrapped
+func (ws WrapStrategy) Previous() error {
+	return ws.previousErr
+}
+
+// Last returns the error wrapping the previous one
+func (ws WrapStrategy) Last() error {
+	return ws.lastErr
+}
+
+// Apply the wrap strategy by wrapping previousErr within lastErr
+func (ws WrapStrategy) Apply() error {
+	if ws.lastErr == nil {
+		return nil
+	}
+	return &wrappedError{cause: ws.previousErr, mapped: ws.lastErr}
+}
+
+// Outcome returns OutcomeMapped
+func (ws WrapStrategy) Outcome() Outcome {
+	return OutcomeMapped
+}
+
+// WithRule returns a copy of the strategy holding the rule which matched the error
+func (ws WrapStrategy) WithRule(rule Rule) WrapStrategy {
+	ws.details = ws.details.withRule(rule)
+	return ws
+}
+
+// WithMatch returns a copy of the strategy holding which of the errors was matched
+func (ws WrapStrategy) WithMatch(match Match) WrapStrategy {
+	ws.details = ws.details.withMatch(match)
+	return ws
+}
+
+// wrappedError is the error resulting from a WrapStrategy
+type wrappedError struct {
+	cause  error
+	mapped error
+}
+
+// Error returns the message of the mapped error only
+func (we *wrappedError) Error() string {
+	return we.mapped.Error()
+}
+
+// Unwrap returns the cause and the mapped error, from the oldest to the most recent like combined errors
+func (we *wrappedError) Unwrap() []error {
+	return []error{we.cause, we.mapped}
+}
+
+// details holds what is known about how an error was matched, shared by the strategies
+type details struct {
+	rule  *Rule
+	match *Match
+}
+
+func (d details) withRule(rule Rule) details {
+	d.rule = &rule
+	return d
+}
+
+func (d details) withMatch(match Match) details {
+	d.match = &match
+	return d
+}
+
+// Rule returns the rule which matched the error, when known
+func (d details) Rule() (Rule, bool) {
+	if d.rule == nil {
+		return Rule{}, false
+	}
+	return *d.rule, true
+}
+
+// Match returns which of the errors was matched, when known
+func (d details) Match() (Match, bool) {
+	if d.match == nil {
+		return Match{}, false
+	}
+	return *d.match, true
+}
+
+// strategyKind is the strategy used for an error a rule maps to
+type strategyKind int
+
+const (
+	strategyAppend strategyKind = iota
+	strategyReplace
+	strategyWrap
+)
+
+// strategyTarget marks the error a rule maps to with the strategy to use
+// it implements Error so that the mappers keep it as it is
+type strategyTarget struct {
+	err      error
+	strategy strategyKind
+}
+
+// ReplaceWith marks the error a rule maps to, so that it replaces the mapped error instead of being appended to it
+// e.g.: NewListMapper().Append(sql.ErrNoRows, ReplaceWith(ErrUserNotFound))
+func ReplaceWith(err error) Error {
+	return strategyTarget{err: err, strategy: strategyReplace}
+}
+
+// WrapWith marks the error a rule maps to, so that it wraps the mapped error instead of being appended to it:
+// only its message is kept while the mapped error is still available through errors.Is and errors.As
+// e.g.: NewListMapper().Append(sql.ErrNoRows, WrapWith(ErrUserNotFound))
+func WrapWith(err error) Error {
+	return strategyTarget{err: err, strategy: strategyWrap}
+}
+
+// Error returns the message of the marked error
+func (st strategyTarget) Error() string {
+	return st.err.Error()
+}
+
+// Hashable returns the marker itself, the marked error is only used as a target
+func (st strategyTarget) Hashable() error {
+	return st
+}
+
+// Is is an alias for Equal added to support go 1.13 errors
+func (st strategyTarget) Is(err error) bool {
+	return st.Equal(err)
+}
+
+// Equal compares the marked error with err
+func (st strategyTarget) Equal(err error) bool {
+	return castError(st.err).Equal(err)
+}
+
+// Format returns the format of the marked error
+func (st strategyTarget) Format() string {
+	return castError(st.err).Format()
+}
+
+// Args returns the arguments of the marked error
+func (st strategyTarget) Args() []interface{} {
+	return castError(st.err).Args()
+}
+
+// newStrategy returns the MapResult mapping previous to target,
+// using the strategy target is marked with, AppendStrategy otherwise
+// templates held by target are rendered with the arguments of the matched error
+func newStrategy(previous, target error, rule Rule, match Match) MapResult {
+	kind := strategyAppend
+	if st, ok := target.(strategyTarget); ok {
+		target, kind = st.err, st.strategy
+	}
+	target = renderTemplate(target, match.Err)
+
+	switch kind {
+	case strategyReplace:
+		return NewReplaceStrategy(previous, target).WithRule(rule).WithMatch(match)
+	case strategyWrap:
+		return NewWrapStrategy(previous, target).WithRule(rule).WithMatch(match)
+	}
+	return NewAppendStrategy(previous, target).WithRule(rule).WithMatch(match)
+}
@@ -0,0 +1,107 @@
+package maperr_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iZettle/maperr/v4"
+)
+
+func TestStrategies(t *testing.T) {
+	errNoRows := errors.New("no rows")
+	errUserNotFound := errors.New("user not found")
+
+	tests := []struct {
+		name          string
+		mapper        maperr.Mapper
+		expectedErr   string
+		expectedCause bool
+	}{
+		{
+			name: "append with a HashableMapper",
+			mapper: maperr.NewHashableMapper().
+				Append(errNoRows, errUserNotFound),
+			expectedErr:   "no rows; user not found",
+			expectedCause: true,
+		},
+		{
+			name: "replace with a HashableMapper",
+			mapper: maperr.NewHashableMapper().
+				Append(errNoRows, maperr.ReplaceWith(errUserNotFound)),
+			expectedErr: "user not found",
+		},
+		{
+			name: "replace with a ListMapper",
+			mapper: maperr.NewListMapper().
+				Append(errNoRows, maperr.ReplaceWith(errUserNotFound)),
+			expectedErr: "user not found",
+		},
+		{
+			name: "wrap with a HashableMapper",
+			mapper: maperr.NewHashableMapper().
+				Append(errNoRows, maperr.WrapWith(errUserNotFound)),
+			expectedErr:   "user not found",
+			expectedCause: true,
+		},
+		{
+			name: "wrap with a ListMapper",
+			mapper: maperr.NewListMapper().
+				Append(errNoRows, maperr.WrapWith(errUserNotFound)),
+			expectedErr:   "user not found",
+			expectedCause: true,
+		},
+		{
+			name: "wrap with a FuncMapper",
+			mapper: maperr.NewFuncMapper().
+				Append(func(err error) bool { return errors.Is(err, errNoRows) }, maperr.WrapWith(errUserNotFound)),
+			expectedErr:   "user not found",
+			expectedCause: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := maperr.NewMultiErr(test.mapper).Mapped(errNoRows, nil)
+
+			require.EqualError(t, actual, test.expectedErr)
+			assert.ErrorIs(t, actual, errUserNotFound)
+			assert.Equal(t, test.expectedCause, errors.Is(actual, errNoRows))
+		})
+	}
+}
+
+func TestStrategies_MappedWithStatus(t *testing.T) {
+	errNoRows := errors.New("no rows")
+
+	errMapper := maperr.NewMultiErr(
+		maperr.NewListMapper().
+			Append(errNoRows, maperr.ReplaceWith(maperr.WithStatus("user not found", http.StatusNotFound))),
+	)
+
+	actual := errMapper.MappedWithStatus(errNoRows, nil)
+	require.NotNil(t, actual)
+	assert.Equal(t, http.StatusNotFound, actual.Status())
+	assert.EqualError(t, actual, "user not found")
+}
+
+func TestStrategies_Layers(t *testing.T) {
+	errNoRows := errors.New("no rows")
+	errUserNotFound := errors.New("user not found")
+	errNotFound := errors.New("not found")
+
+	domainErr := maperr.NewMultiErr(
+		maperr.NewHashableMapper().
+			Append(errNoRows, maperr.WrapWith(errUserNotFound)),
+	).Mapped(errNoRows, nil)
+
+	handlerErr := maperr.NewMultiErr(
+		maperr.NewHashableMapper().
+			Append(errUserNotFound, errNotFound),
+	).Mapped(domainErr, nil)
+
+	assert.EqualError(t, handlerErr, "user not found; not found")
+	assert.ErrorIs(t, handlerErr, errNoRows)
+}
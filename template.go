@@ -0,0 +1,126 @@
+package maperr
+
+// Redacted replaces the arguments redacted with RedactArgs
+const Redacted = "[REDACTED]"
+
+// TemplateOption allows to choose which arguments of the mapped error a template is rendered with
+type TemplateOption func(*messageTemplate)
+
+// SelectArgs renders the template with the arguments of the mapped error at the given indexes, in the given order
+// e.g.: SelectArgs(1, 0) renders "%v %v" from Errorf("%s %s", "a", "b") as "b a"
+func SelectArgs(indexes ...int) TemplateOption {
+	return func(mt *messageTemplate) {
+		mt.selected = append([]int{}, indexes...)
+	}
+}
+
+// RedactArgs replaces the arguments of the mapped error at the given indexes by Redacted,
+// indexes are the positions of the arguments in the mapped error, before any selection
+// redacted arguments should be rendered with %v or %s
+func RedactArgs(indexes ...int) TemplateOption {
+	return func(mt *messageTemplate) {
+		mt.redacted = append(mt.redacted, indexes...)
+	}
+}
+
+// messageTemplate renders a message from the arguments of a mapped error
+type messageTemplate struct {
+	format   string
+	selected []int
+	redacted []int
+}
+
+func newMessageTemplate(format string, opts ...TemplateOption) *messageTemplate {
+	mt := &messageTemplate{format: format}
+	for _, opt := range opts {
+		opt(mt)
+	}
+	return mt
+}
+
+// render returns the formatted error built from the arguments of source,
+// arguments missing from source are rendered by fmt as %!v(MISSING)
+func (mt *messageTemplate) render(source error) Error {
+	args := append([]interface{}(nil), argsOf(source)...)
+	for _, k := range mt.redacted {
+		if k >= 0 && k < len(args) {
+			args[k] = Redacted
+		}
+	}
+	if mt.selected != nil {
+		selected := make([]interface{}, 0, len(mt.selected))
+		for _, k := range mt.selected {
+			if k >= 0 && k < len(args) {
+				selected = append(selected, args[k])
+			}
+		}
+		args = selected
+	}
+	return Errorf(mt.format, args...)
+}
+
+// templateTarget is the error a rule maps to which is rendered from the arguments of the mapped error
+type templateTarget struct {
+	template *messageTemplate
+}
+
+// Template returns an error rendered from the arguments of the error it is mapped from
+// e.g.: NewListMapper().Appendf("user %s not found", Template("user %[1]s does not exist"))
+// the rendered error is created with Errorf, so it can be matched by its format
+func Template(format string, opts ...TemplateOption) Error {
+	return templateTarget{template: newMessageTemplate(format, opts...)}
+}
+
+// MessageTemplate renders the message of an error with status from the arguments of the error it is mapped from
+// e.g.: WithStatus("user not found", http.StatusNotFound, MessageTemplate("user %[1]s does not exist"))
+func MessageTemplate(format string, opts ...TemplateOption) StatusOption {
+	return func(ews *errorWithStatus) {
+		ews.template = newMessageTemplate(format, opts...)
+	}
+}
+
+// renderTemplate renders the template held by target with the arguments of source,
+// targets holding no template are returned as they are
+func renderTemplate(target, source error) error {
+	switch withTemplate := target.(type) {
+	case templateTarget:
+		return withTemplate.template.render(source)
+	case errorWithStatus:
+		if withTemplate.template != nil {
+			withTemplate.err = withTemplate.template.render(source)
+			withTemplate.template = nil
+			return withTemplate
+		}
+	}
+	return target
+}
+
+// Error returns the format of the template
+func (tt templateTarget) Error() string {
+	return tt.template.format
+}
+
+// Hashable returns the template itself
+func (tt templateTarget) Hashable() error {
+	return tt
+}
+
+// Is is an alias for Equal added to support go 1.13 errors
+func (tt templateTarget) Is(err error) bool {
+	return tt.Equal(err)
+}
+
+// Equal compares the format of the template with err
+func (tt templateTarget) Equal(err error) bool {
+	return newUnformattedError(tt.template.format).Equal(err)
+}
+
+// Format returns the format of the template
+func (tt templateTarget) Format() string {
+	return tt.template.format
+}
+
+// Args returns nil, the arguments are only known once rendered
+func (tt templateTarget) Args() []interface{} {
+	return nil
+}
@@ -0,0 +1,104 @@
+package maperr_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iZettle/maperr/v4"
+)
+
+func TestTemplate(t *testing.T) {
+	const formatUserNotFound = "user %s not found in %s"
+
+	tests := []struct {
+		name        string
+		target      error
+		expectedErr string
+	}{
+		{
+			name:        "all arguments",
+			target:      maperr.Template("user %[1]s does not exist"),
+			expectedErr: "user 42 does not exist",
+		},
+		{
+			name:        "selected arguments",
+			target:      maperr.Template("%v: no user %v", maperr.SelectArgs(1, 0)),
+			expectedErr: "users: no user 42",
+		},
+		{
+			name:        "redacted arguments",
+			target:      maperr.Template("user %v not found in %v", maperr.RedactArgs(1)),
+			expectedErr: "user 42 not found in [REDACTED]",
+		},
+		{
+			name:        "redacted then selected arguments",
+			target:      maperr.Template("user %v not found", maperr.RedactArgs(0), maperr.SelectArgs(0)),
+			expectedErr: "user [REDACTED] not found",
+		},
+		{
+			name:        "replaced by a template",
+			target:      maperr.ReplaceWith(maperr.Template("user %[1]s does not exist")),
+			expectedErr: "user 42 does not exist",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errMapper := maperr.NewMultiErr(
+				maperr.NewListMapper().
+					Appendf(formatUserNotFound, test.target),
+			)
+
+			actual := errMapper.Mapped(maperr.Errorf(formatUserNotFound, "42", "users"), nil)
+
+			require.Error(t, actual)
+			assert.Equal(t, test.expectedErr, maperr.LastAppended(actual).Error())
+		})
+	}
+}
+
+func TestTemplate_RenderedErrorCanBeMatched(t *testing.T) {
+	domainErr := maperr.NewMultiErr(
+		maperr.NewListMapper().
+			Appendf("user %s not found", maperr.ReplaceWith(maperr.Template("no user %s"))),
+	).Mapped(maperr.Errorf("user %s not found", "42"), nil)
+
+	var mapped maperr.Error
+	require.True(t, errors.As(domainErr, &mapped))
+	assert.Equal(t, "no user %s", mapped.Format())
+	assert.Equal(t, []interface{}{"42"}, mapped.Args())
+
+	handlerErr := maperr.NewMultiErr(
+		maperr.NewListMapper().
+			Appendf("no user %s", maperr.ReplaceWith(errors.New("user not found"))),
+	).Mapped(domainErr, nil)
+	assert.EqualError(t, handlerErr, "user not found")
+}
+
+func TestMessageTemplate(t *testing.T) {
+	errMapper := maperr.NewMultiErr(
+		maperr.NewListMapper().
+			Appendf("user %s not found", maperr.WithStatusCode("USER_NOT_FOUND", "user not found", http.StatusNotFound,
+				maperr.MessageTemplate("user %s does not exist"))),
+	)
+
+	actual := errMapper.MappedWithStatus(maperr.Errorf("user %s not found", "42"), nil)
+
+	require.NotNil(t, actual)
+	assert.EqualError(t, actual, "user 42 does not exist")
+	assert.Equal(t, http.StatusNotFound, actual.Status())
+	assert.Equal(t, "USER_NOT_FOUND", actual.Code())
+}
+
+func TestError_FormatAndArgs(t *testing.T) {
+	err := maperr.Errorf("user %s not found in %s", "42", "users")
+	assert.Equal(t, "user %s not found in %s", err.Format())
+	assert.Equal(t, []interface{}{"42", "users"}, err.Args())
+
+	noFormat := maperr.NewError("user not found")
+	assert.Equal(t, "user not found", noFormat.Format())
+	assert.Empty(t, noFormat.Args())
+}
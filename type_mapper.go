@@ -0,0 +1,62 @@
+package maperr
+
+import (
+	"reflect"
+)
+
+// TypeMapper is a Mapper which matches errors by type, like errors.As does,
+// and builds the mapped error from the typed value
+// e.g.: NewTypeMapper(func(err *json.SyntaxError) error { ... })
+type TypeMapper[E error] struct {
+	build func(E) error
+}
+
+// NewTypeMapper return a new TypeMapper which maps errors of type E
+// to the error returned by build
+// when build returns nil the error is considered as not mapped
+func NewTypeMapper[E error](build func(E) error) TypeMapper[E] {
+	return TypeMapper[E]{
+		build: build,
+	}
+}
+
+// MapErr an error of type E to the error built from it
+// every error held by err is compared, see walk for the order in which they are compared
+func (tm TypeMapper[E]) MapErr(err error) MapResult {
+	return mapRules(err, tm)
+}
+
+// ExplainErr lists the comparisons made by MapErr
+func (tm TypeMapper[E]) ExplainErr(err error) []Attempt {
+	return explainRules(err, tm)
+}
+
+func (tm TypeMapper[E]) matchRule(err error) Attempt {
+	matcher := "type " + reflect.TypeOf((*E)(nil)).Elem().String()
+
+	target, ok := asType[E](err)
+	if !ok {
+		return notMatched(err, "error is not of "+matcher)
+	}
+	mapped := tm.build(target)
+	if mapped == nil {
+		return notMatched(err, "build returned nil")
+	}
+	return matched(err, Rule{
+		Matcher: matcher,
+		Target:  mapped,
+		Outcome: OutcomeMapped,
+	})
+}
+
+// asType behaves like errors.As on a single error, without following its Unwrap() chain
+func asType[E error](err error) (E, bool) {
+	if target, ok := err.(E); ok {
+		return target, true
+	}
+	var target E
+	if as, ok := err.(interface{ As(interface{}) bool }); ok && as.As(&target) {
+		return target, true
+	}
+	return target, false
+}
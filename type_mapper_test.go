@@ -0,0 +1,143 @@
+package maperr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iZettle/maperr/v4"
+)
+
+func TestTypeMapper_MappedWithStatus(t *testing.T) {
+	var syntaxErr *json.SyntaxError
+	err := json.Unmarshal([]byte(`{"foo":`), &struct{}{})
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("expected a *json.SyntaxError got %T", err)
+	}
+
+	errMapper := maperr.NewMultiErr(
+		maperr.NewTypeMapper(func(err *json.SyntaxError) error {
+			return maperr.WithStatus(fmt.Sprintf("malformed body at offset %d", err.Offset), http.StatusBadRequest)
+		}),
+		maperr.NewTypeMapper(func(err *fs.PathError) error {
+			if !errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return maperr.WithStatus("file not found", http.StatusNotFound)
+		}),
+	)
+
+	tests := []struct {
+		name           string
+		givenError     error
+		expectedErr    string
+		expectedStatus int
+	}{
+		{
+			name:           "error is not matched",
+			givenError:     errors.New("random error"),
+			expectedErr:    "Internal Server Error",
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "json syntax error is mapped with offset",
+			givenError:     maperr.Append(errors.New("decode body"), err),
+			expectedErr:    "malformed body at offset 7",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "path error is mapped",
+			givenError:     fmt.Errorf("read config: %w", &os.PathError{Op: "open", Path: "/foo", Err: fs.ErrNotExist}),
+			expectedErr:    "file not found",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "path error is not mapped when build returns nil",
+			givenError:     &os.PathError{Op: "open", Path: "/foo", Err: fs.ErrPermission},
+			expectedErr:    "Internal Server Error",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actualErr := errMapper.MappedWithStatus(test.givenError, maperr.WithStatusInternalServerError)
+			assert.EqualError(t, actualErr, test.expectedErr)
+			assert.Equal(t, test.expectedStatus, actualErr.Status())
+			assert.Equal(t, test.givenError, actualErr.Unwrap())
+		})
+	}
+}
+
+// validationErrors holds the errors of every invalid field
+type validationErrors struct {
+	fields []error
+}
+
+func (ve *validationErrors) Error() string {
+	return fmt.Sprintf("%d invalid fields", len(ve.fields))
+}
+
+func (ve *validationErrors) Unwrap() []error {
+	return ve.fields
+}
+
+func TestTypeMapper_MultiError(t *testing.T) {
+	errMapper := maperr.NewMultiErr(
+		maperr.NewTypeMapper(func(err *validationErrors) error {
+			return maperr.WithStatus(fmt.Sprintf("%d fields are invalid", len(err.fields)), http.StatusBadRequest)
+		}),
+	)
+
+	given := fmt.Errorf("create user: %w", &validationErrors{
+		fields: []error{errors.New("name is empty"), errors.New("email is invalid")},
+	})
+
+	actualErr := errMapper.MappedWithStatus(given, maperr.WithStatusInternalServerError)
+	assert.EqualError(t, actualErr, "2 fields are invalid")
+	assert.Equal(t, http.StatusBadRequest, actualErr.Status())
+}
+
+// codeError is an error carrying a code
+type codeError struct {
+	code int
+}
+
+func (ce *codeError) Error() string {
+	return fmt.Sprintf("code %d", ce.code)
+}
+
+func TestTypeMapper_CombinedErrors(t *testing.T) {
+	errMapper := maperr.NewMultiErr(
+		maperr.NewTypeMapper(func(err *codeError) error {
+			return fmt.Errorf("mapped from code %d", err.code)
+		}),
+	)
+
+	tests := []struct {
+		name  string
+		given error
+	}{
+		{
+			name:  "combined with multierr",
+			given: maperr.Combine(&codeError{code: 1}, &codeError{code: 2}),
+		},
+		{
+			name:  "combined with errors.Join",
+			given: maperr.CombineJoin(&codeError{code: 1}, &codeError{code: 2}),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := errMapper.MapErr(test.given)
+			if assert.NotNil(t, actual) {
+				assert.EqualError(t, actual.Last(), "mapped from code 2")
+			}
+		})
+	}
+}
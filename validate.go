@@ -0,0 +1,192 @@
+package maperr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RuleLister is implemented by the mappers which can list their rules
+type RuleLister interface {
+	// Rules lists the rules of the mapper, in the order they are compared
+	Rules() []Rule
+}
+
+// IssueKind is the kind of problem found by Validate
+type IssueKind int
+
+// Kinds of issues found by Validate
+const (
+	// IssueDuplicateKey a rule has the same key as an earlier rule
+	IssueDuplicateKey IssueKind = iota
+	// IssueIgnoredAndMapped an error is both ignored and mapped, only the earlier rule is used
+	IssueIgnoredAndMapped
+	// IssueUnreachable the key of a rule is matched by an earlier, broader, rule
+	IssueUnreachable
+)
+
+// String returns a human readable representation of the kind of issue
+func (k IssueKind) String() string {
+	switch k {
+	case IssueDuplicateKey:
+		return "duplicate key"
+	case IssueIgnoredAndMapped:
+		return "ignored and mapped"
+	case IssueUnreachable:
+		return "unreachable"
+	}
+	return "unknown"
+}
+
+// Issue describes a rule which is never used for its key, as an earlier rule matches it first
+type Issue struct {
+	Kind IssueKind
+	// Mapper is the position of the mapper holding Rule within the MultiErr
+	Mapper int
+	// Rule is the rule which is never used for its key
+	Rule Rule
+	// ShadowedByMapper is the position of the mapper which matches the key first
+	ShadowedByMapper int
+	// ShadowedBy is the rule which matches the key first,
+	// zero value when the mapper does not tell which rule matched
+	ShadowedBy Rule
+}
+
+// String returns a human readable representation of the issue
+func (i Issue) String() string {
+	shadowedBy := fmt.Sprintf("mapper #%d", i.ShadowedByMapper)
+	if i.ShadowedBy != (Rule{}) {
+		shadowedBy += " rule " + i.ShadowedBy.String()
+	}
+	return fmt.Sprintf("%s: mapper #%d rule %s is shadowed by %s", i.Kind, i.Mapper, i.Rule, shadowedBy)
+}
+
+// ValidationReport lists the issues found by Validate, in the order of the rules
+type ValidationReport struct {
+	Issues []Issue
+}
+
+// String returns a human readable representation of the report, one issue per line
+func (r ValidationReport) String() string {
+	lines := make([]string, len(r.Issues))
+	for k := range r.Issues {
+		lines[k] = r.Issues[k].String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Err returns an error listing the issues, nil when there is none
+func (r ValidationReport) Err() error {
+	if len(r.Issues) == 0 {
+		return nil
+	}
+	return errors.New("maperr: invalid rules:\n" + r.String())
+}
+
+// Validate checks that every rule of the mappers implementing RuleLister can be used:
+// the key of a rule must not be the key of an earlier rule, nor be matched by an earlier rule,
+// earlier rules being the rules of the mappers before the one holding the rule,
+// then the rules before it in the same mapper
+func (m MultiErr) Validate() ValidationReport {
+	var report ValidationReport
+	var earlier []Issue
+	for k := range m.mappers {
+		lister, ok := m.mappers[k].(RuleLister)
+		if !ok {
+			continue
+		}
+		for _, rule := range lister.Rules() {
+			if rule.Key == nil {
+				continue
+			}
+			issue, ok := duplicateOf(earlier, k, rule)
+			if !ok {
+				issue, ok = m.shadowing(k, rule)
+			}
+			if ok {
+				report.Issues = append(report.Issues, issue)
+			}
+			earlier = append(earlier, Issue{Mapper: k, Rule: rule})
+		}
+	}
+	return report
+}
+
+// duplicateOf looks for an earlier rule with the same key as the rule at mapper index k
+func duplicateOf(earlier []Issue, k int, rule Rule) (Issue, bool) {
+	for _, e := range earlier {
+		if !sameKey(e.Rule, rule) {
+			continue
+		}
+		return Issue{
+			Kind:             issueKind(rule, e.Rule, e.Rule.Outcome),
+			Mapper:           k,
+			Rule:             rule,
+			ShadowedByMapper: e.Mapper,
+			ShadowedBy:       e.Rule,
+		}, true
+	}
+	return Issue{}, false
+}
+
+// shadowing looks for the first rule matching the key of the rule at mapper index k, before it
+func (m MultiErr) shadowing(k int, rule Rule) (Issue, bool) {
+	for i := 0; i <= k; i++ {
+		res := m.mappers[i].MapErr(rule.Key)
+		if !isMatched(res) {
+			continue
+		}
+
+		var shadowedBy Rule
+		var known bool
+		if provider, ok := res.(RuleProvider); ok {
+			shadowedBy, known = provider.Rule()
+		}
+		if i == k && (!known || shadowedBy.Index < 0 || shadowedBy.Index >= rule.Index) {
+			// matched by the rule itself
+			return Issue{}, false
+		}
+
+		return Issue{
+			Kind:             issueKind(rule, shadowedBy, res.Outcome()),
+			Mapper:           k,
+			Rule:             rule,
+			ShadowedByMapper: i,
+			ShadowedBy:       shadowedBy,
+		}, true
+	}
+	return Issue{}, false
+}
+
+// issueKind tells why a rule is shadowed by an earlier rule with the given outcome
+func issueKind(rule, shadowedBy Rule, outcome Outcome) IssueKind {
+	if (rule.Outcome == OutcomeIgnored) != (outcome == OutcomeIgnored) {
+		return IssueIgnoredAndMapped
+	}
+	if sameKey(shadowedBy, rule) {
+		return IssueDuplicateKey
+	}
+	return IssueUnreachable
+}
+
+// sameKey checks if two rules have the same key,
+// hashable keys are compared by identity as a HashableMapper does, other keys with Equal
+func sameKey(a, b Rule) bool {
+	if a.Key == nil || b.Key == nil {
+		return false
+	}
+	if a.Index < 0 && b.Index < 0 {
+		return a.Key == b.Key
+	}
+	return castError(a.Key).Equal(b.Key)
+}
+
+// NewStrictMultiErr returns a new instance of MultiErr,
+// or an error listing the issues found by Validate
+func NewStrictMultiErr(mapper ...Mapper) (MultiErr, error) {
+	m := NewMultiErr(mapper...)
+	if err := m.Validate().Err(); err != nil {
+		return MultiErr{}, err
+	}
+	return m, nil
+}
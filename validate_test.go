@@ -0,0 +1,120 @@
+package maperr_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iZettle/maperr/v4"
+)
+
+func TestMultiErr_Validate(t *testing.T) {
+	errNoRows := errors.New("no rows")
+	errCanceled := errors.New("canceled")
+	errUserNotFound := maperr.WithStatus("user not found", http.StatusNotFound)
+	errUserLocked := maperr.WithStatus("user is locked", http.StatusLocked)
+
+	tests := []struct {
+		name     string
+		given    maperr.MultiErr
+		expected []maperr.IssueKind
+	}{
+		{
+			name: "valid rules",
+			given: maperr.NewMultiErr(
+				maperr.NewIgnoreListMapper().
+					Append(errCanceled),
+				maperr.NewHashableMapper().
+					Append(errNoRows, errUserNotFound),
+				maperr.NewListMapper().
+					Appendf("user %s is locked", errUserLocked),
+			),
+		},
+		{
+			name: "duplicate key in a ListMapper",
+			given: maperr.NewMultiErr(
+				maperr.NewListMapper().
+					Appendf("user %s is locked", errUserLocked).
+					Append(maperr.Errorf("user %s is locked", "bob"), errUserNotFound),
+			),
+			expected: []maperr.IssueKind{maperr.IssueDuplicateKey},
+		},
+		{
+			name: "duplicate key across a HashableMapper and a ListMapper",
+			given: maperr.NewMultiErr(
+				maperr.NewHashableMapper().
+					Append(errNoRows, errUserNotFound),
+				maperr.NewListMapper().
+					Append(errNoRows, errUserLocked),
+			),
+			expected: []maperr.IssueKind{maperr.IssueDuplicateKey},
+		},
+		{
+			name: "error ignored and mapped",
+			given: maperr.NewMultiErr(
+				maperr.NewHashableMapper().
+					Append(errCanceled, errUserNotFound),
+				maperr.NewIgnoreListMapper().
+					Append(errCanceled),
+			),
+			expected: []maperr.IssueKind{maperr.IssueIgnoredAndMapped},
+		},
+		{
+			name: "rule shadowed by a broader mapper",
+			given: maperr.NewMultiErr(
+				maperr.NewFuncMapper().
+					Append(func(err error) bool { return strings.HasPrefix(err.Error(), "no ") }, errUserLocked),
+				maperr.NewHashableMapper().
+					Append(errNoRows, errUserNotFound),
+			),
+			expected: []maperr.IssueKind{maperr.IssueUnreachable},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			report := test.given.Validate()
+
+			var actual []maperr.IssueKind
+			for _, issue := range report.Issues {
+				actual = append(actual, issue.Kind)
+			}
+			assert.Equal(t, test.expected, actual, report.String())
+		})
+	}
+}
+
+func TestMultiErr_Validate_Issue(t *testing.T) {
+	report := maperr.NewMultiErr(
+		maperr.NewListMapper().
+			Append(errors.New("no rows"), errors.New("user not found")),
+		maperr.NewIgnoreListMapper().
+			Append(errors.New("no rows")),
+	).Validate()
+
+	require.Len(t, report.Issues, 1)
+	issue := report.Issues[0]
+	assert.Equal(t, 1, issue.Mapper)
+	assert.Equal(t, 0, issue.ShadowedByMapper)
+	assert.Equal(t, `ignored and mapped: mapper #1 rule #0 error "no rows" -> ignored is shadowed by mapper #0 rule #0 error "no rows" -> "user not found"`, issue.String())
+}
+
+func TestNewStrictMultiErr(t *testing.T) {
+	_, err := maperr.NewStrictMultiErr(
+		maperr.NewListMapper().
+			Append(errors.New("no rows"), errors.New("user not found")).
+			Append(errors.New("no rows"), errors.New("user does not exist")),
+	)
+	assert.EqualError(t, err, "maperr: invalid rules:\n"+
+		`duplicate key: mapper #0 rule #1 error "no rows" -> "user does not exist" is shadowed by mapper #0 rule #0 error "no rows" -> "user not found"`)
+
+	m, err := maperr.NewStrictMultiErr(
+		maperr.NewListMapper().
+			Append(errors.New("no rows"), errors.New("user not found")),
+	)
+	require.NoError(t, err)
+	assert.EqualError(t, m.Mapped(errors.New("no rows"), nil), "no rows; user not found")
+}
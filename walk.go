@@ -0,0 +1,130 @@
+package maperr
+
+import (
+	"errors"
+	"reflect"
+
+	"go.uber.org/multierr"
+)
+
+// multierrType is the type of the errors combined by go.uber.org/multierr
+var multierrType = reflect.TypeOf(multierr.Combine(errors.New("first"), errors.New("second")))
+
+// joinType is the type of the errors combined by errors.Join
+var joinType = reflect.TypeOf(errors.Join(errors.New("first"), errors.New("second")))
+
+// walk visits err and every error it holds, until visit returns true
+//
+// The order in which errors are visited defines which match wins
+// when several errors of the tree are matched by a Mapper:
+//
+//   - an error is visited before the errors it wraps, following its Unwrap() chain
+//   - combined errors (go.uber.org/multierr, errors.Join or any error implementing Unwrap() []error)
+//     have their errors visited from the most recently appended to the oldest one
+//   - errors combined by go.uber.org/multierr or errors.Join are not visited themselves,
+//     other errors implementing Unwrap() []error are visited after the errors they hold
+//
+// e.g.: Combine(first, fmt.Errorf("second: %w", third), fourth)
+// is visited as fourth, second, third, first
+func walk(err error, visit func(error) bool) bool {
+	if err == nil {
+		return false
+	}
+
+	if errList := combinedErrors(err); errList != nil {
+		for i := len(errList) - 1; i >= 0; i-- {
+			if walk(errList[i], visit) {
+				return true
+			}
+		}
+		return !isContainer(err) && visit(err)
+	}
+
+	if visit(err) {
+		return true
+	}
+
+	return walk(errors.Unwrap(err), visit)
+}
+
+// isContainer checks if err was made by go.uber.org/multierr or errors.Join,
+// which only hold errors and have no meaning of their own
+func isContainer(err error) bool {
+	errType := reflect.TypeOf(err)
+	return errType == multierrType || errType == joinType
+}
+
+// combinedErrors returns the list of errors held by a combined error
+// or nil when err is not a combined error
+func combinedErrors(err error) []error {
+	if combined, ok := err.(interface{ Unwrap() []error }); ok {
+		return combined.Unwrap()
+	}
+	if reflect.TypeOf(err) == multierrType {
+		return multierr.Errors(err)
+	}
+	return nil
+}
+
+// asChain behaves like errors.As, following the Unwrap() chain of err
+// but without looking into combined errors, which are walked error by error
+func asChain[E error](err error) (E, bool) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if target, ok := err.(E); ok {
+			return target, true
+		}
+		if combinedErrors(err) != nil {
+			break
+		}
+	}
+	var zero E
+	return zero, false
+}
+
+// flatten returns the errors held by combined errors, from the oldest to the most recently appended,
+// nested combined errors are flattened as well
+func flatten(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	errList := combinedErrors(err)
+	if errList == nil {
+		return []error{err}
+	}
+
+	var flattened []error
+	for _, wrapped := range errList {
+		flattened = append(flattened, flatten(wrapped)...)
+	}
+	return flattened
+}
+
+// isHashable checks if an error can be used as a map key without panicking
+func isHashable(err error) bool {
+	return err != nil && isHashableValue(reflect.ValueOf(err))
+}
+
+// isHashableValue checks if v can be hashed, looking at the dynamic values held by its interface fields
+// as a comparable struct holding an error which is not comparable still panics when hashed
+func isHashableValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Interface:
+		return v.IsNil() || isHashableValue(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !isHashableValue(v.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !isHashableValue(v.Index(i)) {
+				return false
+			}
+		}
+		return true
+	}
+	return v.Type().Comparable()
+}
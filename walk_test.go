@@ -0,0 +1,118 @@
+package maperr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/multierr"
+)
+
+func TestWalk_Order(t *testing.T) {
+	first := errors.New("first")
+	third := errors.New("third")
+	second := fmt.Errorf("second: %w", third)
+	fourth := errors.New("fourth")
+	fifth := errors.New("fifth")
+	sixth := errors.New("sixth")
+
+	tests := []struct {
+		name     string
+		given    error
+		expected []string
+	}{
+		{
+			name:     "nil error",
+			given:    nil,
+			expected: nil,
+		},
+		{
+			name:     "single error",
+			given:    first,
+			expected: []string{"first"},
+		},
+		{
+			name:     "wrapped error",
+			given:    second,
+			expected: []string{"second: third", "third"},
+		},
+		{
+			name:     "multierr with wrapped error",
+			given:    multierr.Combine(first, second, fourth),
+			expected: []string{"fourth", "second: third", "third", "first"},
+		},
+		{
+			name:     "errors.Join nested in a multierr",
+			given:    multierr.Combine(first, errors.Join(fourth, fifth), sixth),
+			expected: []string{"sixth", "fifth", "fourth", "first"},
+		},
+		{
+			name:     "multierr wrapped with fmt.Errorf",
+			given:    fmt.Errorf("update user: %w", multierr.Combine(first, fourth)),
+			expected: []string{"update user: first; fourth", "fourth", "first"},
+		},
+		{
+			name:     "fmt.Errorf with several %w is visited after its errors",
+			given:    fmt.Errorf("%w: %w", first, fourth),
+			expected: []string{"fourth", "first", "first: fourth"},
+		},
+		{
+			name:     "custom error listing its errors is not expanded",
+			given:    errorList{first, fourth},
+			expected: []string{"2 errors"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var visited []string
+			walk(test.given, func(err error) bool {
+				visited = append(visited, err.Error())
+				return false
+			})
+			assert.Equal(t, test.expected, visited)
+		})
+	}
+}
+
+func TestWalk_StopsOnVisit(t *testing.T) {
+	var visited []string
+	found := walk(multierr.Combine(errors.New("first"), errors.New("second")), func(err error) bool {
+		visited = append(visited, err.Error())
+		return err.Error() == "second"
+	})
+	assert.True(t, found)
+	assert.Equal(t, []string{"second"}, visited)
+}
+
+// errorList is an error holding several errors
+// without implementing Unwrap() []error
+type errorList []error
+
+func (el errorList) Error() string {
+	return fmt.Sprintf("%d errors", len(el))
+}
+
+func (el errorList) Errors() []error {
+	return el
+}
+
+func TestIsHashable(t *testing.T) {
+	assert.False(t, isHashable(nil))
+	assert.True(t, isHashable(errors.New("boom")))
+	assert.True(t, isHashable(Errorf("user %s is locked", "bob").(formattedError).Hashable()))
+	assert.False(t, isHashable(errorList{errors.New("boom")}))
+	assert.False(t, isHashable(Errorf("user %s is locked", "bob")))
+	assert.False(t, isHashable(valueWrapper{err: Errorf("user %s is locked", "bob")}))
+	assert.True(t, isHashable(valueWrapper{err: errors.New("boom")}))
+	assert.True(t, isHashable(valueWrapper{}))
+}
+
+// valueWrapper is a comparable error holding another error
+type valueWrapper struct {
+	err error
+}
+
+func (vw valueWrapper) Error() string {
+	return "wrapped"
+}